@@ -0,0 +1,210 @@
+package aliaspolicy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	TagAlias    = "alias"
+	TagCharset  = "alias_charset"
+	TagReserved = "alias_reserved"
+	TagBlocked  = "alias_blocked"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("invalid alias policy")
+)
+
+// DefaultReserved is always reserved on top of the configured words and the registered routes.
+var DefaultReserved = []string{
+	"about", "account", "admin", "administrator", "api", "app", "assets", "auth", "billing", "blog",
+	"config", "dashboard", "debug", "docs", "favicon", "health", "healthz", "help", "home", "login",
+	"logout", "metrics", "oauth", "password", "ready", "register", "root", "security", "settings",
+	"signin", "signup", "static", "status", "support", "system", "user", "users", "www",
+}
+
+type Policy struct {
+	mu        *sync.RWMutex
+	charset   map[rune]struct{}
+	minLength int
+	maxLength int
+	reserved  map[string]struct{}
+	blocked   []string
+}
+
+func New(cfg config.AliasPolicy) (*Policy, error) {
+	const op = "internal/aliaspolicy/New"
+
+	if cfg.Charset == "" || cfg.MinLength < 1 || cfg.MaxLength < cfg.MinLength {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidPolicy)
+	}
+
+	policy := &Policy{
+		mu:        &sync.RWMutex{},
+		charset:   make(map[rune]struct{}, len(cfg.Charset)),
+		minLength: cfg.MinLength,
+		maxLength: cfg.MaxLength,
+		reserved:  make(map[string]struct{}, len(cfg.Reserved)),
+	}
+
+	for _, r := range cfg.Charset {
+		if r == '/' || r == '?' || r == '#' || r == '%' {
+			return nil, fmt.Errorf("%s: %w: charset must not contain %q", op, ErrInvalidPolicy, r)
+		}
+
+		policy.charset[r] = struct{}{}
+	}
+
+	policy.Reserve(DefaultReserved...)
+	policy.Reserve(cfg.Reserved...)
+
+	if cfg.BlocklistPath == "" {
+		return policy, nil
+	}
+
+	file, err := os.Open(cfg.BlocklistPath)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer file.Close()
+
+	policy.blocked, err = readBlocklist(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+func readBlocklist(r io.Reader) ([]string, error) {
+	const op = "internal/aliaspolicy/readBlocklist"
+
+	out := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		out = append(out, word)
+	}
+
+	err := scanner.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (p *Policy) Reserve(words ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+
+		if word != "" {
+			p.reserved[word] = struct{}{}
+		}
+	}
+}
+
+func (p *Policy) ReservePattern(pattern string) {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+
+	segment, _, _ := strings.Cut(strings.TrimPrefix(pattern, "/"), "/")
+
+	if strings.HasPrefix(segment, "{") {
+		return
+	}
+
+	p.Reserve(segment)
+}
+
+func (p *Policy) ValidCharset(alias string) bool {
+	for _, r := range alias {
+		if _, ok := p.charset[r]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Policy) ValidLength(alias string) bool {
+	length := utf8.RuneCountInString(alias)
+
+	return length >= p.minLength && length <= p.maxLength
+}
+
+func (p *Policy) Reserved(alias string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.reserved[strings.ToLower(alias)]
+
+	return ok
+}
+
+func (p *Policy) Blocked(alias string) bool {
+	alias = strings.ToLower(alias)
+
+	for _, word := range p.blocked {
+		if strings.Contains(alias, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allowed reports whether a generated alias is neither reserved nor blocked.
+// Charset and length are up to the generator configuration.
+func (p *Policy) Allowed(alias string) bool {
+	return !p.Reserved(alias) && !p.Blocked(alias)
+}
+
+// The "alias" tag expands into min/max plus the policy tags so that
+// validaterequests.Validate can tell which rule failed.
+func (p *Policy) Register(v *validator.Validate) error {
+	const op = "internal/aliaspolicy/Register"
+
+	rules := map[string]func(alias string) bool{
+		TagCharset:  p.ValidCharset,
+		TagReserved: func(alias string) bool { return !p.Reserved(alias) },
+		TagBlocked:  func(alias string) bool { return !p.Blocked(alias) },
+	}
+
+	for tag, rule := range rules {
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return rule(fl.Field().String())
+		})
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	v.RegisterAlias(TagAlias, fmt.Sprintf("min=%d,max=%d,%s,%s,%s", p.minLength, p.maxLength, TagCharset, TagReserved, TagBlocked))
+
+	return nil
+}
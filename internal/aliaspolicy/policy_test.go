@@ -0,0 +1,158 @@
+package aliaspolicy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/go-playground/validator/v10"
+)
+
+func newTestPolicy(t *testing.T, cfg config.AliasPolicy) *Policy {
+	t.Helper()
+
+	if cfg.Charset == "" {
+		cfg.Charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+	}
+
+	if cfg.MinLength == 0 {
+		cfg.MinLength = 3
+	}
+
+	if cfg.MaxLength == 0 {
+		cfg.MaxLength = 12
+	}
+
+	policy, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return policy
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.AliasPolicy
+	}{
+		{name: "empty charset", cfg: config.AliasPolicy{MinLength: 1, MaxLength: 2}},
+		{name: "zero min length", cfg: config.AliasPolicy{Charset: "ab", MaxLength: 2}},
+		{name: "max below min", cfg: config.AliasPolicy{Charset: "ab", MinLength: 3, MaxLength: 2}},
+		{name: "slash in charset", cfg: config.AliasPolicy{Charset: "ab/", MinLength: 1, MaxLength: 2}},
+		{name: "percent in charset", cfg: config.AliasPolicy{Charset: "ab%", MinLength: 1, MaxLength: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("New() error = %v, want %v", err, ErrInvalidPolicy)
+			}
+		})
+	}
+}
+
+func TestPolicyRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+
+	err := os.WriteFile(path, []byte("# comment\n\n  Spam \nscam\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := newTestPolicy(t, config.AliasPolicy{Reserved: []string{"Promo", " "}, BlocklistPath: path})
+	policy.ReservePattern("GET /stats/{alias}")
+	policy.ReservePattern("POST /{alias}/unlock")
+	policy.ReservePattern("/qr")
+
+	tests := []struct {
+		alias    string
+		charset  bool
+		length   bool
+		reserved bool
+		blocked  bool
+	}{
+		{alias: "my-link_1", charset: true, length: true},
+		{alias: "ab", charset: true, length: false},
+		{alias: "abcdefghijklm", charset: true, length: false},
+		{alias: "ab.cd", charset: false, length: true},
+		{alias: "päth", charset: false, length: true},
+		{alias: "admin", charset: true, length: true, reserved: true},
+		{alias: "DEBUG", charset: true, length: true, reserved: true},
+		{alias: "promo", charset: true, length: true, reserved: true},
+		{alias: "stats", charset: true, length: true, reserved: true},
+		{alias: "qr", charset: true, length: false, reserved: true},
+		{alias: "unlock", charset: true, length: true},
+		{alias: "admins", charset: true, length: true},
+		{alias: "freeSPAM1", charset: true, length: true, blocked: true},
+		{alias: "scam", charset: true, length: true, blocked: true},
+		{alias: "comment", charset: true, length: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := policy.ValidCharset(tt.alias); got != tt.charset {
+				t.Errorf("ValidCharset() = %v, want %v", got, tt.charset)
+			}
+
+			if got := policy.ValidLength(tt.alias); got != tt.length {
+				t.Errorf("ValidLength() = %v, want %v", got, tt.length)
+			}
+
+			if got := policy.Reserved(tt.alias); got != tt.reserved {
+				t.Errorf("Reserved() = %v, want %v", got, tt.reserved)
+			}
+
+			if got := policy.Blocked(tt.alias); got != tt.blocked {
+				t.Errorf("Blocked() = %v, want %v", got, tt.blocked)
+			}
+
+			if got, want := policy.Allowed(tt.alias), !tt.reserved && !tt.blocked; got != want {
+				t.Errorf("Allowed() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPolicyRegister(t *testing.T) {
+	policy := newTestPolicy(t, config.AliasPolicy{})
+	v := validator.New()
+
+	err := policy.Register(v)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	type request struct {
+		Alias string `validate:"omitempty,alias"`
+	}
+
+	tests := []struct {
+		alias string
+		tag   string
+	}{
+		{alias: ""},
+		{alias: "my-link"},
+		{alias: "ab", tag: "min"},
+		{alias: "abcdefghijklm", tag: "max"},
+		{alias: "a.b.c", tag: TagCharset},
+		{alias: "admin", tag: TagReserved},
+	}
+
+	for _, tt := range tests {
+		err := v.Struct(request{Alias: tt.alias})
+
+		var errs validator.ValidationErrors
+
+		switch {
+		case tt.tag == "" && err != nil:
+			t.Errorf("alias %q: unexpected error %v", tt.alias, err)
+		case tt.tag != "" && !errors.As(err, &errs):
+			t.Errorf("alias %q: error = %v, want validation error", tt.alias, err)
+		case tt.tag != "" && errs[0].ActualTag() != tt.tag:
+			t.Errorf("alias %q: failed tag = %q, want %q", tt.alias, errs[0].ActualTag(), tt.tag)
+		}
+	}
+}
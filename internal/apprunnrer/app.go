@@ -2,6 +2,7 @@ package apprunner
 
 import (
 	"context"
+	"expvar"
 	"log"
 	"log/slog"
 	"net/http"
@@ -12,13 +13,22 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Cwby333/url-shorter/internal/aliaspolicy"
 	"github.com/Cwby333/url-shorter/internal/apprunnrer/gracefuler"
+	"github.com/Cwby333/url-shorter/internal/apprunnrer/sweeper"
 	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/geoip"
 	"github.com/Cwby333/url-shorter/internal/logger"
 	"github.com/Cwby333/url-shorter/internal/repository/postgres"
 	"github.com/Cwby333/url-shorter/internal/repository/redis"
+	"github.com/Cwby333/url-shorter/internal/services/clicksservice"
+	"github.com/Cwby333/url-shorter/internal/services/invalidationservice"
+	"github.com/Cwby333/url-shorter/internal/services/outboxservice"
+	"github.com/Cwby333/url-shorter/internal/services/qrservice"
+	"github.com/Cwby333/url-shorter/internal/services/statsservice"
 	"github.com/Cwby333/url-shorter/internal/services/urlsservice"
 	"github.com/Cwby333/url-shorter/internal/services/usersservice"
+	"github.com/Cwby333/url-shorter/internal/services/webhookservice"
 	"github.com/Cwby333/url-shorter/internal/transport/http/ratelimiter"
 	"github.com/Cwby333/url-shorter/internal/transport/http/server"
 
@@ -81,7 +91,136 @@ func (app App) Run() {
 	}
 	closer.Add(client)
 
-	urlService, err := urlsservice.New(pool, client, logger)
+	invalidator, err := invalidationservice.New(client, client, logger, cfg.Invalidation)
+
+	if err != nil {
+		logger.Error("cache invalidation", slog.String("error", err.Error()))
+		return
+	}
+	invalidator.Start()
+	closer.Add(invalidator)
+
+	geo, err := geoip.Load(cfg.GeoIP.Path)
+
+	if err != nil {
+		logger.Error("load geoip database", slog.String("error", err.Error()))
+		return
+	}
+
+	clickWriter, err := clicksservice.New(pool, geo, logger, cfg.Clicks)
+
+	if err != nil {
+		logger.Error("", slog.String("error", err.Error()))
+		return
+	}
+	clickWriter.Start()
+	closer.Add(clickWriter)
+
+	aliasPolicy, err := aliaspolicy.New(cfg.AliasPolicy)
+
+	if err != nil {
+		logger.Error("alias policy", slog.String("error", err.Error()))
+		return
+	}
+
+	aliasGenerator, err := urlsservice.NewAliasGenerator(cfg.Aliases, pool, pool, client, aliasPolicy, logger)
+
+	if err != nil {
+		logger.Error("alias generator", slog.String("error", err.Error()))
+		return
+	}
+	closer.Add(aliasGenerator)
+
+	destinations, err := urlsservice.NewDestinationPolicy(cfg.Destinations, cfg.Redirect, cfg.HTTPServer, logger)
+
+	if err != nil {
+		logger.Error("destination policy", slog.String("error", err.Error()))
+		return
+	}
+
+	if cfg.Destinations.BlocklistPath != "" && cfg.Destinations.ReloadInterval > 0 {
+		blocklistSweeper := sweeper.New(cfg.Destinations.ReloadInterval, logger.Logger, sweeper.Job{
+			Name: "reload destination blocklist",
+			Run:  destinations.Reload,
+		})
+		blocklistSweeper.Start(ctx)
+		closer.Add(blocklistSweeper)
+	}
+
+	webhookService, err := webhookservice.New(pool, destinations, logger, cfg.Webhooks)
+
+	if err != nil {
+		logger.Error("webhook service", slog.String("error", err.Error()))
+		return
+	}
+	webhookService.Start()
+	closer.Add(webhookService)
+
+	webhooksSweeper := sweeper.New(cfg.Webhooks.PollInterval, logger.Logger, sweeper.Job{
+		Name: "deliver webhooks",
+		Run:  webhookService.Deliver,
+	})
+	webhooksSweeper.Start(ctx)
+	closer.Add(webhooksSweeper)
+
+	outboxSinks, err := outboxservice.NewSinks(cfg.Outbox, client, destinations.DialControl)
+
+	if err != nil {
+		logger.Error("outbox sinks", slog.String("error", err.Error()))
+		return
+	}
+
+	outboxSinks = append(outboxSinks, webhookService)
+
+	outboxRelay, err := outboxservice.New(pool, outboxSinks, logger, cfg.Outbox)
+
+	if err != nil {
+		logger.Error("outbox relay", slog.String("error", err.Error()))
+		return
+	}
+
+	outboxSweeper := sweeper.New(cfg.Outbox.PollInterval, logger.Logger, sweeper.Job{
+		Name: "relay outbox",
+		Run:  outboxRelay.Relay,
+	})
+	outboxSweeper.Start(ctx)
+	closer.Add(outboxSweeper)
+
+	urlService, err := urlsservice.New(pool, client, clickWriter, aliasGenerator, destinations, webhookService, invalidator, logger)
+
+	if err != nil {
+		logger.Error("", slog.String("error", err.Error()))
+		return
+	}
+
+	urlsSweeper := sweeper.New(cfg.Sweeper.Interval, logger.Logger, sweeper.Job{
+		Name: "purge expired urls",
+		Run: func(ctx context.Context) (int64, error) {
+			return urlService.PurgeExpired(ctx, cfg.Sweeper.Grace, cfg.Sweeper.Archive)
+		},
+	}, sweeper.Job{
+		Name: "purge deleted urls",
+		Run: func(ctx context.Context) (int64, error) {
+			return urlService.PurgeDeleted(ctx, cfg.Trash.Quarantine)
+		},
+	}, sweeper.Job{
+		Name: "prune popular aliases",
+		Run:  urlService.PrunePopularAliases,
+	}, sweeper.Job{
+		Name: "purge published outbox events",
+		Run:  outboxRelay.Purge,
+	})
+	urlsSweeper.Start(ctx)
+	closer.Add(urlsSweeper)
+
+	statsService, err := statsservice.New(pool, logger)
+
+	if err != nil {
+		logger.Error("", slog.String("error", err.Error()))
+		return
+	}
+
+	qrService, err := qrservice.New(pool, client, logger)
 
 	if err != nil {
 		logger.Error("", slog.String("error", err.Error()))
@@ -103,7 +242,7 @@ func (app App) Run() {
 	}
 	closer.Add(rateLimiter)
 
-	server, err := httpserver.New(ctx, cfg.HTTPServer, urlService, logger, userService, rateLimiter, ctx)
+	server, err := httpserver.New(ctx, cfg.HTTPServer, urlService, statsService, qrService, aliasPolicy, geo, logger, userService, webhookService, rateLimiter, cfg.Redirect, ctx)
 
 	if err != nil {
 		logger.Error("server init", slog.String("error", err.Error()))
@@ -134,6 +273,7 @@ func (app App) Run() {
 	mux := http.NewServeMux()
 	mux.Handle("/debug/pprof/{index}", http.HandlerFunc(pprof.Index))
 	mux.Handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
+	mux.Handle("/debug/vars", expvar.Handler())
 	pprofServer := http.Server{
 		Addr:    "localhost:9999",
 		Handler: mux,
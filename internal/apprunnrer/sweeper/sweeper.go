@@ -0,0 +1,91 @@
+package sweeper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	DefaultTimeoutForOneJob = time.Duration(time.Second * 30)
+)
+
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) (int64, error)
+}
+
+type Sweeper struct {
+	interval time.Duration
+	jobs     []Job
+	logger   *slog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(interval time.Duration, logger *slog.Logger, jobs ...Job) Sweeper {
+	return Sweeper{
+		interval: interval,
+		jobs:     jobs,
+		logger:   logger.With("component", "sweeper"),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s Sweeper) Start(ctx context.Context) {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.runJobs(ctx)
+			}
+		}
+	}()
+}
+
+func (s Sweeper) runJobs(ctx context.Context) {
+	for _, job := range s.jobs {
+		jobCtx, cancel := context.WithTimeout(ctx, DefaultTimeoutForOneJob)
+		count, err := job.Run(jobCtx)
+		cancel()
+
+		if err != nil {
+			s.logger.Error("sweeper job", slog.String("job", job.Name), slog.String("error", err.Error()))
+			continue
+		}
+
+		if count == 0 {
+			s.logger.Debug("sweeper job", slog.String("job", job.Name), slog.Int64("affected", count))
+			continue
+		}
+
+		s.logger.Info("sweeper job", slog.String("job", job.Name), slog.Int64("affected", count))
+	}
+}
+
+func (s Sweeper) Close() chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		close(s.stop)
+		<-s.done
+		ch <- nil
+	}()
+
+	return ch
+}
+
+func (s Sweeper) ContextInfo() string {
+	return "sweeper"
+}
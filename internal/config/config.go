@@ -3,24 +3,38 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
 var (
-	ErrCantFindConfig = errors.New("cant find config")
+	ErrCantFindConfig      = errors.New("cant find config")
+	ErrAliasLengthOverflow = errors.New("aliases length is too large for the sequence generator and alphabet")
 )
 
 type Config struct {
-	Env         string `yaml:"env" env-required:"true"`
-	HTTPServer  `yaml:"http-server" env-required:"true"`
-	Database    `yaml:"database" env-required:"true"`
-	JWT         `yaml:"jwt" env-required:"true"`
-	Redis       `yaml:"redis" env-required:"true"`
-	RateLimiter `yaml:"rate-limiter" env-required:"true"`
+	Env          string `yaml:"env" env-required:"true"`
+	HTTPServer   `yaml:"http-server" env-required:"true"`
+	Database     `yaml:"database" env-required:"true"`
+	JWT          `yaml:"jwt" env-required:"true"`
+	Redis        `yaml:"redis" env-required:"true"`
+	RateLimiter  `yaml:"rate-limiter" env-required:"true"`
+	Redirect     `yaml:"redirect"`
+	Sweeper      `yaml:"sweeper"`
+	Clicks       `yaml:"clicks"`
+	GeoIP        `yaml:"geoip"`
+	Aliases      `yaml:"aliases"`
+	AliasPolicy  `yaml:"alias-policy"`
+	Destinations `yaml:"destinations"`
+	Trash        `yaml:"trash"`
+	Webhooks     `yaml:"webhooks"`
+	Outbox       `yaml:"outbox"`
+	Invalidation `yaml:"invalidation"`
 }
 
 type HTTPServer struct {
@@ -71,6 +85,89 @@ type RateLimiter struct {
 	TTL   time.Duration `yaml:"ttl" env-required:"true"`
 }
 
+type Redirect struct {
+	StatusCode int    `yaml:"status-code" env-default:"302"`
+	BaseURL    string `yaml:"base-url"`
+}
+
+type Sweeper struct {
+	Interval time.Duration `yaml:"interval" env-default:"1h"`
+	Grace    time.Duration `yaml:"grace" env-default:"0s"`
+	Archive  bool          `yaml:"archive"`
+}
+
+type Trash struct {
+	Quarantine time.Duration `yaml:"quarantine" env-default:"720h"`
+}
+
+type Webhooks struct {
+	PollInterval time.Duration `yaml:"poll-interval" env-default:"5s"`
+	BatchSize    int           `yaml:"batch-size" env-default:"50"`
+	BufferSize   int           `yaml:"buffer-size" env-default:"1024"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts  int           `yaml:"max-attempts" env-default:"8"`
+	BackoffBase  time.Duration `yaml:"backoff-base" env-default:"30s"`
+	BackoffMax   time.Duration `yaml:"backoff-max" env-default:"6h"`
+}
+
+type Outbox struct {
+	Sinks         []string      `yaml:"sinks" env-default:"redis"`
+	PollInterval  time.Duration `yaml:"poll-interval" env-default:"1s"`
+	BatchSize     int           `yaml:"batch-size" env-default:"100"`
+	Lease         time.Duration `yaml:"lease" env-default:"30s"`
+	MaxAttempts   int           `yaml:"max-attempts" env-default:"10"`
+	BackoffBase   time.Duration `yaml:"backoff-base" env-default:"5s"`
+	BackoffMax    time.Duration `yaml:"backoff-max" env-default:"1h"`
+	Retention     time.Duration `yaml:"retention" env-default:"168h"`
+	Stream        string        `yaml:"stream" env-default:"events"`
+	StreamMaxLen  int64         `yaml:"stream-max-len" env-default:"100000"`
+	WebhookURL    string        `yaml:"webhook-url"`
+	WebhookSecret string        `yaml:"webhook-secret"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"10s"`
+}
+
+type Invalidation struct {
+	Channel       string        `yaml:"channel" env-default:"urls:invalidate"`
+	PingInterval  time.Duration `yaml:"ping-interval" env-default:"30s"`
+	BackoffMin    time.Duration `yaml:"backoff-min" env-default:"500ms"`
+	BackoffMax    time.Duration `yaml:"backoff-max" env-default:"30s"`
+	FlushEvery    time.Duration `yaml:"flush-every" env-default:"1m"`
+	ReorderWindow time.Duration `yaml:"reorder-window" env-default:"2s"`
+}
+
+type Clicks struct {
+	BufferSize    int           `yaml:"buffer-size" env-default:"10000"`
+	BatchSize     int           `yaml:"batch-size" env-default:"500"`
+	FlushInterval time.Duration `yaml:"flush-interval" env-default:"2s"`
+}
+
+type Aliases struct {
+	Generator string `yaml:"generator" env-default:"random"`
+	Length    int    `yaml:"length" env-default:"6"`
+	Alphabet  string `yaml:"alphabet" env-default:"QWERTYUIOPASDFGHJKLZXCVBNMqwertyuiopasdfghjklzxcvbnm1234567890"`
+	PoolSize  int    `yaml:"pool-size" env-default:"1000"`
+}
+
+type AliasPolicy struct {
+	Charset       string   `yaml:"charset" env-default:"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"`
+	MinLength     int      `yaml:"min-length" env-default:"3"`
+	MaxLength     int      `yaml:"max-length" env-default:"32"`
+	Reserved      []string `yaml:"reserved"`
+	BlocklistPath string   `yaml:"blocklist-path"`
+}
+
+type Destinations struct {
+	Schemes        []string      `yaml:"schemes" env-default:"http,https"`
+	BlocklistPath  string        `yaml:"blocklist-path"`
+	ReloadInterval time.Duration `yaml:"reload-interval" env-default:"30s"`
+	ResolveTimeout time.Duration `yaml:"resolve-timeout" env-default:"2s"`
+	AllowPrivate   bool          `yaml:"allow-private"`
+}
+
+type GeoIP struct {
+	Path string `yaml:"path"`
+}
+
 func Load(env string) (Config, error) {
 	const op = "internal/config/Load"
 
@@ -101,5 +198,32 @@ func Load(env string) (Config, error) {
 		return Config{}, fmt.Errorf("%s: %w", op, err)
 	}
 
+	err = cfg.Aliases.Validate()
+
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return cfg, nil
 }
+
+// Validate checks that every alias of the configured length maps to an int64
+// sequence value, so the sequence generator cannot overflow.
+func (a Aliases) Validate() error {
+	if a.Generator != "sequence" {
+		return nil
+	}
+
+	base := int64(utf8.RuneCountInString(a.Alphabet))
+	space := int64(1)
+
+	for range a.Length {
+		if base > 1 && space > math.MaxInt64/base {
+			return ErrAliasLengthOverflow
+		}
+
+		space *= base
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package clicks
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DimensionReferrer = "referrer"
+	DimensionBrowser  = "browser"
+	DimensionCountry  = "country"
+	DimensionVariant  = "variant"
+
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+
+	DirectReferrer = "(direct)"
+	UnknownCountry = "unknown"
+)
+
+type Click struct {
+	URLID       int       `db:"url_id" json:"url_id"`
+	Alias       string    `db:"alias" json:"alias"`
+	ClickedAt   time.Time `db:"clicked_at" json:"clicked_at"`
+	Referrer    string    `db:"referrer" json:"referrer"`
+	UserAgent   string    `db:"user_agent" json:"user_agent"`
+	IP          string    `db:"ip" json:"ip"`
+	RequestID   string    `db:"request_id" json:"request_id"`
+	Country     string    `db:"country" json:"country"`
+	Browser     string    `db:"browser" json:"browser"`
+	Variant     int       `db:"variant" json:"variant"`
+	Destination string    `db:"destination" json:"destination"`
+}
+
+type Bucket struct {
+	Time  time.Time `db:"bucket" json:"time"`
+	Count int64     `db:"count" json:"count"`
+}
+
+type Counter struct {
+	Value string `db:"value" json:"value"`
+	Count int64  `db:"count" json:"count"`
+}
+
+type Stats struct {
+	Alias     string    `json:"alias"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Interval  string    `json:"interval"`
+	Total     int64     `json:"total"`
+	Clicks    []Bucket  `json:"clicks"`
+	Referrers []Counter `json:"referrers"`
+	Browsers  []Counter `json:"browsers"`
+	Countries []Counter `json:"countries"`
+	Variants  []Counter `json:"variants"`
+}
+
+func (c Click) ReferrerHost() string {
+	if c.Referrer == "" {
+		return DirectReferrer
+	}
+
+	ref, err := url.Parse(c.Referrer)
+
+	if err != nil || ref.Hostname() == "" {
+		return DirectReferrer
+	}
+
+	return strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+}
@@ -0,0 +1,32 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AggregateURL  = "url"
+	AggregateUser = "user"
+
+	EventURLCreated  = "url.created"
+	EventURLUpdated  = "url.updated"
+	EventURLDeleted  = "url.deleted"
+	EventURLRestored = "url.restored"
+	EventURLPurged   = "url.purged"
+
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserBlocked = "user.blocked"
+)
+
+type Event struct {
+	ID          int64           `db:"id" json:"-"`
+	EventID     string          `db:"event_id" json:"id"`
+	Aggregate   string          `db:"aggregate" json:"aggregate"`
+	AggregateID string          `db:"aggregate_id" json:"aggregate_id"`
+	Type        string          `db:"event" json:"event"`
+	Payload     json.RawMessage `db:"payload" json:"payload"`
+	Attempts    int             `db:"attempts" json:"-"`
+	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
+}
@@ -1,7 +1,244 @@
 package urls
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	SortByCreated = "created"
+	SortByClicks  = "clicks"
+
+	WindowHour = "hour"
+	WindowDay  = "day"
+	WindowWeek = "week"
+
+	ImportSkip      = "skip"
+	ImportOverwrite = "overwrite"
+	ImportFail      = "fail"
+
+	GroupTags    = "tags"
+	GroupFolders = "folders"
+
+	HistoryUpdate   = "update"
+	HistoryDelete   = "delete"
+	HistoryRollback = "rollback"
+	HistoryRestore  = "restore"
+)
+
 type URL struct {
-	ID    int    `db:"id" json:"id"`
-	URL   string `db:"url" json:"url"`
+	ID              int           `db:"id" json:"id"`
+	URL             string        `db:"url" json:"url"`
+	Alias           string        `db:"alias" json:"alias"`
+	Title           string        `db:"title" json:"title,omitempty"`
+	RedirectCode    int           `db:"redirect_code" json:"redirect_code,omitempty"`
+	OwnerUUID       string        `db:"owner_uuid" json:"owner_uuid,omitempty"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at" json:"updated_at"`
+	Clicks          int64         `db:"clicks" json:"clicks"`
+	ExpiresAt       *time.Time    `db:"expires_at" json:"expires_at,omitempty"`
+	MaxClicks       *int          `db:"max_clicks" json:"max_clicks,omitempty"`
+	RemainingClicks *int          `db:"remaining_clicks" json:"remaining_clicks,omitempty"`
+	Protected       bool          `db:"protected" json:"protected,omitempty"`
+	PasswordHash    string        `db:"password_hash" json:"-"`
+	Folder          string        `db:"folder" json:"folder,omitempty"`
+	Tags            []string      `db:"tags" json:"tags,omitempty"`
+	Destinations    []Destination `db:"destinations" json:"destinations,omitempty"`
+	Sticky          bool          `db:"sticky" json:"sticky,omitempty"`
+	Rules           []Rule        `db:"rules" json:"rules,omitempty"`
+	DeletedAt       *time.Time    `db:"deleted_at" json:"deleted_at,omitempty"`
+	ActiveFrom      *time.Time    `db:"active_from" json:"active_from,omitempty"`
+	ComingSoon      bool          `db:"coming_soon" json:"coming_soon,omitempty"`
+}
+
+type Destination struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// Key identifies the destination by its URL, so it survives reordering of the destination list.
+func (d Destination) Key() string {
+	sum := sha256.Sum256([]byte(d.URL))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+type Rule struct {
+	OS       string `json:"os,omitempty"`
+	Device   string `json:"device,omitempty"`
+	Language string `json:"language,omitempty"`
+	Country  string `json:"country,omitempty"`
+	URL      string `json:"url"`
+}
+
+type Visitor struct {
+	OS        string
+	Device    string
+	Languages []string
+	Country   string
+}
+
+// Matches reports whether every condition set on the rule holds for the visitor.
+// A language rule "de" matches any of the visitor's languages equal to it or regional tags such as "de-AT".
+func (r Rule) Matches(v Visitor) bool {
+	if r.OS != "" && !strings.EqualFold(r.OS, v.OS) {
+		return false
+	}
+
+	if r.Device != "" && !strings.EqualFold(r.Device, v.Device) {
+		return false
+	}
+
+	if r.Country != "" && !strings.EqualFold(r.Country, v.Country) {
+		return false
+	}
+
+	if r.Language != "" {
+		want := strings.ToLower(r.Language)
+
+		return slices.ContainsFunc(v.Languages, func(lang string) bool {
+			lang = strings.ToLower(lang)
+
+			return lang == want || strings.HasPrefix(lang, want+"-")
+		})
+	}
+
+	return true
+}
+
+// MatchRule returns the first matching rule, trying the visitor's languages in preference order.
+// With "fr,de;q=0.9" every rule is checked against "fr" before any is checked against "de".
+func (u URL) MatchRule(v Visitor) (Rule, bool) {
+	languages := v.Languages
+
+	if len(languages) == 0 {
+		languages = []string{""}
+	}
+
+	for _, lang := range languages {
+		single := v
+		single.Languages = []string{lang}
+
+		for _, rule := range u.Rules {
+			if rule.Matches(single) {
+				return rule, true
+			}
+		}
+	}
+
+	return Rule{}, false
+}
+
+func (u URL) Expired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
+}
+
+func (u URL) Pending(now time.Time) bool {
+	return u.ActiveFrom != nil && now.Before(*u.ActiveFrom)
+}
+
+func (u URL) TotalWeight() int {
+	total := 0
+
+	for _, dest := range u.Destinations {
+		total += dest.Weight
+	}
+
+	return total
+}
+
+// Variant maps roll, a number in [0, TotalWeight()), onto the index of a weighted destination.
+func (u URL) Variant(roll int) int {
+	for i, dest := range u.Destinations {
+		if roll < dest.Weight {
+			return i
+		}
+
+		roll -= dest.Weight
+	}
+
+	return len(u.Destinations) - 1
+}
+
+// VariantByKey returns the index of the destination with the given Key.
+func (u URL) VariantByKey(key string) (int, bool) {
+	for i, dest := range u.Destinations {
+		if dest.Key() == key {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+type BatchItem struct {
+	URL      URL
+	Password string
+}
+
+type Activation struct {
+	ActiveFrom *time.Time
+	ComingSoon bool
+}
+
+type SaveResult struct {
+	ID    int
+	Alias string
+	Err   error
+}
+
+type ImportSource interface {
+	Next() bool
+	URL() URL
+	Err() error
+}
+
+type ImportResult struct {
+	Total          int64    `json:"total"`
+	Inserted       int64    `json:"inserted"`
+	Updated        int64    `json:"updated"`
+	Skipped        int64    `json:"skipped"`
+	UpdatedAliases []string `json:"-"`
+}
+
+type ListFilter struct {
+	OwnerUUID   string
+	Query       string
+	Tag         string
+	Folder      string
+	SortBy      string
+	Limit       int
+	AfterID     int
+	AfterClicks int64
+}
+
+type SearchFilter struct {
+	OwnerUUID string
+	Query     string
+	Limit     int
+	Offset    int
+}
+
+type PopularAlias struct {
 	Alias string `db:"alias" json:"alias"`
+	Count int64  `db:"count" json:"count"`
+}
+
+type Group struct {
+	ID        int64     `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Links     int64     `db:"links" json:"links"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Version struct {
+	Version   int       `db:"version" json:"version"`
+	Action    string    `db:"action" json:"action"`
+	ChangedBy string    `db:"changed_by" json:"changed_by"`
+	OldURL    string    `db:"old_url" json:"old_url"`
+	NewURL    string    `db:"new_url" json:"new_url,omitempty"`
+	ChangedAt time.Time `db:"changed_at" json:"changed_at"`
 }
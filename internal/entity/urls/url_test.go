@@ -0,0 +1,137 @@
+package urls
+
+import "testing"
+
+func TestRuleMatches(t *testing.T) {
+	visitor := Visitor{OS: "android", Device: "mobile", Languages: []string{"de-AT"}, Country: "AT"}
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		visitor Visitor
+		want    bool
+	}{
+		{name: "no conditions", rule: Rule{}, visitor: visitor, want: true},
+		{name: "os", rule: Rule{OS: "android"}, visitor: visitor, want: true},
+		{name: "os case insensitive", rule: Rule{OS: "Android"}, visitor: visitor, want: true},
+		{name: "os mismatch", rule: Rule{OS: "ios"}, visitor: visitor, want: false},
+		{name: "device mismatch", rule: Rule{Device: "desktop"}, visitor: visitor, want: false},
+		{name: "country", rule: Rule{Country: "at"}, visitor: visitor, want: true},
+		{name: "country mismatch", rule: Rule{Country: "DE"}, visitor: visitor, want: false},
+		{name: "language exact", rule: Rule{Language: "de-AT"}, visitor: visitor, want: true},
+		{name: "language region", rule: Rule{Language: "de"}, visitor: visitor, want: true},
+		{name: "language prefix is not a region", rule: Rule{Language: "d"}, visitor: visitor, want: false},
+		{name: "language narrower than visitor", rule: Rule{Language: "de-AT"}, visitor: Visitor{Languages: []string{"de"}}, want: false},
+		{name: "language any of list", rule: Rule{Language: "de"}, visitor: Visitor{Languages: []string{"fr", "de"}}, want: true},
+		{name: "language without header", rule: Rule{Language: "de"}, visitor: Visitor{}, want: false},
+		{name: "all conditions", rule: Rule{OS: "android", Device: "mobile", Language: "de", Country: "AT"}, visitor: visitor, want: true},
+		{name: "one condition fails", rule: Rule{OS: "android", Device: "mobile", Language: "fr", Country: "AT"}, visitor: visitor, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.visitor); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLMatchRule(t *testing.T) {
+	url := URL{Rules: []Rule{
+		{Language: "de", URL: "https://example.com/de"},
+		{Language: "fr", URL: "https://example.com/fr"},
+		{Device: "mobile", URL: "https://example.com/app"},
+	}}
+
+	tests := []struct {
+		name    string
+		visitor Visitor
+		want    string
+	}{
+		{name: "single language", visitor: Visitor{Languages: []string{"de"}}, want: "https://example.com/de"},
+		{name: "second language", visitor: Visitor{Languages: []string{"es", "de"}}, want: "https://example.com/de"},
+		{name: "preferred language beats rule order", visitor: Visitor{Languages: []string{"fr", "de"}}, want: "https://example.com/fr"},
+		{name: "rule order within a language", visitor: Visitor{Device: "mobile", Languages: []string{"de"}}, want: "https://example.com/de"},
+		{name: "non language rule for first language", visitor: Visitor{Device: "mobile", Languages: []string{"es", "de"}}, want: "https://example.com/app"},
+		{name: "no languages", visitor: Visitor{Device: "mobile"}, want: "https://example.com/app"},
+		{name: "no match", visitor: Visitor{Languages: []string{"es"}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := url.MatchRule(tt.visitor)
+
+			if ok != (tt.want != "") || rule.URL != tt.want {
+				t.Errorf("MatchRule() = %q, %v, want %q", rule.URL, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLVariant(t *testing.T) {
+	url := URL{Destinations: []Destination{
+		{URL: "https://a.example.com", Weight: 1},
+		{URL: "https://b.example.com", Weight: 0},
+		{URL: "https://c.example.com", Weight: 3},
+	}}
+
+	if got := url.TotalWeight(); got != 4 {
+		t.Fatalf("TotalWeight() = %d, want 4", got)
+	}
+
+	tests := []struct {
+		roll int
+		want int
+	}{
+		{roll: 0, want: 0},
+		{roll: 1, want: 2},
+		{roll: 2, want: 2},
+		{roll: 3, want: 2},
+		{roll: 4, want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := url.Variant(tt.roll); got != tt.want {
+			t.Errorf("Variant(%d) = %d, want %d", tt.roll, got, tt.want)
+		}
+	}
+}
+
+func TestURLVariantByKey(t *testing.T) {
+	a := Destination{URL: "https://a.example.com", Weight: 1}
+	b := Destination{URL: "https://b.example.com", Weight: 1}
+	c := Destination{URL: "https://c.example.com", Weight: 1}
+
+	if a.Key() == b.Key() {
+		t.Fatalf("Key() collides for %q and %q", a.URL, b.URL)
+	}
+
+	if got := (Destination{URL: a.URL, Weight: 5}).Key(); got != a.Key() {
+		t.Errorf("Key() depends on weight: %q != %q", got, a.Key())
+	}
+
+	tests := []struct {
+		name   string
+		dests  []Destination
+		key    string
+		want   int
+		wantOK bool
+	}{
+		{name: "found", dests: []Destination{a, b}, key: b.Key(), want: 1, wantOK: true},
+		{name: "reordered", dests: []Destination{b, a}, key: b.Key(), want: 0, wantOK: true},
+		{name: "replaced", dests: []Destination{a, c}, key: b.Key(), wantOK: false},
+		{name: "legacy index cookie", dests: []Destination{a, b}, key: "1", wantOK: false},
+		{name: "empty", dests: nil, key: a.Key(), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := URL{Destinations: tt.dests}.VariantByKey(tt.key)
+
+			if ok != tt.wantOK || ok && got != tt.want {
+				t.Errorf("VariantByKey() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
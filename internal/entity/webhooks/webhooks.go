@@ -0,0 +1,60 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventURLCreated = "url.created"
+	EventURLUpdated = "url.updated"
+	EventURLDeleted = "url.deleted"
+	EventURLClicked = "url.clicked"
+
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+var Events = []string{EventURLCreated, EventURLUpdated, EventURLDeleted, EventURLClicked}
+
+type Webhook struct {
+	ID        int64     `db:"id" json:"id"`
+	URL       string    `db:"url" json:"url"`
+	Events    []string  `db:"events" json:"events"`
+	Secret    string    `db:"secret" json:"secret,omitempty"`
+	Active    bool      `db:"active" json:"active"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type Event struct {
+	ID         string    `json:"id,omitempty"`
+	Type       string    `json:"event"`
+	Alias      string    `json:"alias"`
+	URL        string    `json:"url,omitempty"`
+	Referrer   string    `json:"referrer,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type Delivery struct {
+	ID            int64           `db:"id" json:"id"`
+	WebhookID     int64           `db:"webhook_id" json:"webhook_id"`
+	Event         string          `db:"event" json:"event"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	Status        string          `db:"status" json:"status"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	NextAttemptAt time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError     string          `db:"last_error" json:"last_error,omitempty"`
+	ResponseCode  int             `db:"response_code" json:"response_code,omitempty"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	DeliveredAt   *time.Time      `db:"delivered_at" json:"delivered_at,omitempty"`
+}
+
+type Task struct {
+	ID       int64  `db:"id"`
+	Event    string `db:"event"`
+	Payload  []byte `db:"payload"`
+	Attempts int    `db:"attempts"`
+	URL      string `db:"url"`
+	Secret   string `db:"secret"`
+}
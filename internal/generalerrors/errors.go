@@ -13,7 +13,50 @@ var (
 	ErrToManyUseOfRefreshToken = errors.New("to many uses of refresh token")
 
 	ErrAliasAlreadyExists = errors.New("alias already exists")
+	ErrAliasNotSaved      = errors.New("alias could not be saved")
 	ErrAliasNotFound      = errors.New("alias not found")
+	ErrNotAliasOwner      = errors.New("user is not owner of alias")
+	ErrAliasExpired       = errors.New("alias expired")
+	ErrAliasNotActive     = errors.New("alias is not active yet")
+	ErrAliasComingSoon    = errors.New("alias is coming soon")
+	ErrClicksExhausted    = errors.New("alias click limit exhausted")
+	ErrPasswordRequired   = errors.New("password required")
+	ErrVersionNotFound    = errors.New("version not found")
+
+	ErrTagNotFound         = errors.New("tag not found")
+	ErrTagAlreadyExists    = errors.New("tag already exists")
+	ErrFolderNotFound      = errors.New("folder not found")
+	ErrFolderAlreadyExists = errors.New("folder already exists")
+	ErrInvalidGroup        = errors.New("group must be one of: tags folders")
+
+	ErrInvalidRedirectCode   = errors.New("invalid redirect status code")
+	ErrInvalidAliasGenerator = errors.New("invalid alias generator config")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrInvalidSearchQuery    = errors.New("q must contain letters or digits")
+
+	ErrInvalidStatsInterval = errors.New("interval must be one of: hour day")
+	ErrInvalidStatsRange    = errors.New("invalid stats time range")
+	ErrInvalidWindow        = errors.New("window must be one of: hour day week")
+	ErrInvalidImportPolicy  = errors.New("on_conflict must be one of: skip overwrite fail")
+	ErrInvalidImportRow     = errors.New("invalid import row")
+	ErrInvalidFormat        = errors.New("format must be one of: csv ndjson")
+	ErrInvalidQROptions     = errors.New("invalid qr options: format must be png or svg, ecc one of L M Q H, size between 64 and 2048")
+	ErrBaseURLRequired      = errors.New("qr codes require redirect base-url to be configured")
+
+	ErrDestinationScheme   = errors.New("url scheme is not allowed")
+	ErrDestinationHost     = errors.New("url host cannot be resolved")
+	ErrDestinationPrivate  = errors.New("url points to a private network")
+	ErrDestinationLoop     = errors.New("url points to this url shortener")
+	ErrDestinationBlocked  = errors.New("url domain is blocked")
+	ErrInvalidDestinations = errors.New("destinations must contain at least one url with a positive weight")
+	ErrInvalidRules        = errors.New("each rule must set at least one of: os device language country")
+	ErrInvalidActivation   = errors.New("active_from must be before expires_at")
+
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrInvalidWebhookEvents = errors.New("events must be a subset of: url.created url.updated url.deleted url.clicked")
+
+	ErrUnknownOutboxSink = errors.New("unknown outbox sink, expected one of: redis webhook stdout")
+	ErrOutboxWebhookURL  = errors.New("outbox webhook sink requires webhook-url")
 
 	ErrCacheMiss = errors.New("not found in cache")
 
@@ -0,0 +1,141 @@
+package geoip
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidRange = errors.New("invalid ip range")
+)
+
+type ipRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+type DB struct {
+	ranges []ipRange
+}
+
+func Load(path string) (DB, error) {
+	const op = "internal/geoip/Load"
+
+	if path == "" {
+		return DB{}, nil
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return DB{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer file.Close()
+
+	db, err := Read(file)
+
+	if err != nil {
+		return DB{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return db, nil
+}
+
+func Read(r io.Reader) (DB, error) {
+	const op = "internal/geoip/Read"
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	ranges := make([]ipRange, 0)
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return DB{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(record) < 3 {
+			return DB{}, fmt.Errorf("%s: line %d: %w", op, line, ErrInvalidRange)
+		}
+
+		start, errStart := netip.ParseAddr(strings.TrimSpace(record[0]))
+		end, errEnd := netip.ParseAddr(strings.TrimSpace(record[1]))
+
+		if errStart != nil || errEnd != nil {
+			if line == 1 {
+				continue
+			}
+
+			return DB{}, fmt.Errorf("%s: line %d: %w", op, line, ErrInvalidRange)
+		}
+
+		start, end = start.Unmap(), end.Unmap()
+
+		if start.BitLen() != end.BitLen() || end.Less(start) {
+			return DB{}, fmt.Errorf("%s: line %d: %w", op, line, ErrInvalidRange)
+		}
+
+		ranges = append(ranges, ipRange{
+			start:   start,
+			end:     end,
+			country: strings.ToUpper(strings.TrimSpace(record[2])),
+		})
+	}
+
+	slices.SortFunc(ranges, func(a, b ipRange) int {
+		return a.start.Compare(b.start)
+	})
+
+	return DB{
+		ranges: ranges,
+	}, nil
+}
+
+func (db DB) Country(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+
+	if err != nil {
+		host = addr
+	}
+
+	ip, err := netip.ParseAddr(host)
+
+	if err != nil {
+		return ""
+	}
+
+	ip = ip.Unmap()
+
+	i, found := slices.BinarySearchFunc(db.ranges, ip, func(r ipRange, ip netip.Addr) int {
+		return r.start.Compare(ip)
+	})
+
+	if !found {
+		i--
+	}
+
+	if i < 0 || i >= len(db.ranges) {
+		return ""
+	}
+
+	if r := db.ranges[i]; r.end.Compare(ip) >= 0 && r.start.BitLen() == ip.BitLen() {
+		return r.country
+	}
+
+	return ""
+}
@@ -0,0 +1,491 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	MinVersion = 1
+	MaxVersion = 40
+
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+var (
+	ErrDataTooLong  = errors.New("data too long for qr code")
+	ErrInvalidLevel = errors.New("invalid error correction level")
+)
+
+type Level int
+
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+func ParseLevel(s string) (Level, error) {
+	const op = "internal/qrcode/ParseLevel"
+
+	switch strings.ToUpper(s) {
+	case "L":
+		return Low, nil
+	case "", "M":
+		return Medium, nil
+	case "Q":
+		return Quartile, nil
+	case "H":
+		return High, nil
+	}
+
+	return 0, fmt.Errorf("%s: %w", op, ErrInvalidLevel)
+}
+
+func (l Level) String() string {
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+type Code struct {
+	Version int
+	Level   Level
+	Size    int
+
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// Encode builds a byte mode symbol of the smallest version that fits data at the given level.
+func Encode(data []byte, level Level) (*Code, error) {
+	const op = "internal/qrcode/Encode"
+
+	if level < Low || level > High {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidLevel)
+	}
+
+	version := MinVersion
+
+	for ; version <= MaxVersion; version++ {
+		if bitsNeeded(len(data), version) <= dataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	if version > MaxVersion {
+		return nil, fmt.Errorf("%s: %w", op, ErrDataTooLong)
+	}
+
+	capacity := dataCodewords(version, level) * 8
+
+	bits := bitBuffer{}
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits(version))
+
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	code := newCode(version, level)
+	code.drawFunctionPatterns()
+	code.drawCodewords(code.addECCAndInterleave(bits.bytes()))
+
+	best, bestPenalty := 0, math.MaxInt
+
+	for mask := range 8 {
+		code.applyMask(mask)
+		code.drawFormatBits(mask)
+
+		if penalty := code.penalty(); penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+
+		code.applyMask(mask)
+	}
+
+	code.applyMask(best)
+	code.drawFormatBits(best)
+	code.isFunction = nil
+
+	return code, nil
+}
+
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+
+	return 16
+}
+
+func bitsNeeded(length, version int) int {
+	if length >= 1<<countBits(version) {
+		return math.MaxInt
+	}
+
+	return 4 + countBits(version) + length*8
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	return out
+}
+
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+
+	code := &Code{
+		Version:    version,
+		Level:      level,
+		Size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+
+	for i := range size {
+		code.modules[i] = make([]bool, size)
+		code.isFunction[i] = make([]bool, size)
+	}
+
+	return code
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := range c.Size {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	positions := alignmentPositions(c.Version)
+	last := len(positions) - 1
+
+	for i, x := range positions {
+		for j, y := range positions {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+
+			c.drawAlignment(x, y)
+		}
+	}
+
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+
+			if xx < 0 || xx >= c.Size || yy < 0 || yy >= c.Size {
+				continue
+			}
+
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	data := c.Level.formatBits()<<3 | mask
+	rem := data
+
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+
+	c.setFunction(8, c.Size-8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+
+	rem := c.Version
+
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+
+	bits := c.Version<<12 | rem
+
+	for i := range 18 {
+		dark := bits>>i&1 == 1
+		a, b := c.Size-11+i%3, i/3
+
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+func (c *Code) addECCAndInterleave(data []byte) []byte {
+	blocks := eccBlocks[c.Level][c.Version]
+	eccLen := eccCodewordsPerBlock[c.Level][c.Version]
+	raw := rawDataModules(c.Version) / 8
+	shortBlocks := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	divisor := rsDivisor(eccLen)
+	out := make([][]byte, 0, blocks)
+
+	for i, k := 0, 0; i < blocks; i++ {
+		length := shortLen - eccLen
+
+		if i >= shortBlocks {
+			length++
+		}
+
+		block := append([]byte{}, data[k:k+length]...)
+		k += length
+
+		ecc := rsRemainder(block, divisor)
+
+		if i < shortBlocks {
+			block = append(block, 0)
+		}
+
+		out = append(out, append(block, ecc...))
+	}
+
+	result := make([]byte, 0, raw)
+
+	for i := range shortLen + 1 {
+		for j, block := range out {
+			if i != shortLen-eccLen || j >= shortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+
+	return result
+}
+
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+
+		for vert := range c.Size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.isFunction[y][x] {
+				continue
+			}
+
+			var invert bool
+
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+
+			c.modules[y][x] = c.modules[y][x] != invert
+		}
+	}
+}
+
+func (c *Code) penalty() int {
+	result := 0
+	dark := 0
+
+	line := make([]bool, c.Size)
+
+	for _, horizontal := range []bool{true, false} {
+		for i := range c.Size {
+			for j := range c.Size {
+				if horizontal {
+					line[j] = c.modules[i][j]
+				} else {
+					line[j] = c.modules[j][i]
+				}
+			}
+
+			result += linePenalty(line)
+		}
+	}
+
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.modules[y][x] {
+				dark++
+			}
+
+			if x+1 < c.Size && y+1 < c.Size {
+				color := c.modules[y][x]
+
+				if color == c.modules[y][x+1] && color == c.modules[y+1][x] && color == c.modules[y+1][x+1] {
+					result += penaltyN2
+				}
+			}
+		}
+	}
+
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+
+	return result
+}
+
+var finderLike = [2][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func linePenalty(line []bool) int {
+	result := 0
+	run := 1
+
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+
+		if run >= 5 {
+			result += penaltyN1 + run - 5
+		}
+
+		run = 1
+	}
+
+	for i := 0; i+len(finderLike[0]) <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+
+			for j, dark := range pattern {
+				if line[i+j] != dark {
+					match = false
+					break
+				}
+			}
+
+			if match {
+				result += penaltyN3
+			}
+		}
+	}
+
+	return result
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,318 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGFMultiply(t *testing.T) {
+	tests := []struct {
+		x, y, want byte
+	}{
+		{0x00, 0x53, 0x00},
+		{0x01, 0x53, 0x53},
+		{0x02, 0x80, 0x1D},
+		{0x80, 0x80, 0x13},
+		{0x53, 0xCA, 0x8F},
+		{0xFF, 0xFF, 0xE2},
+	}
+
+	for _, tt := range tests {
+		if got := gfMultiply(tt.x, tt.y); got != tt.want {
+			t.Errorf("gfMultiply(%#x, %#x) = %#x, want %#x", tt.x, tt.y, got, tt.want)
+		}
+
+		if got := gfMultiply(tt.y, tt.x); got != tt.want {
+			t.Errorf("gfMultiply(%#x, %#x) = %#x, want %#x", tt.y, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestRSDivisor(t *testing.T) {
+	// x^7 + a^87x^6 + a^229x^5 + a^146x^4 + a^149x^3 + a^238x^2 + a^102x + a^21
+	exponents := []int{87, 229, 146, 149, 238, 102, 21}
+
+	got := rsDivisor(len(exponents))
+
+	for i, exp := range exponents {
+		if want := alphaPow(exp); got[i] != want {
+			t.Errorf("rsDivisor(7)[%d] = %d, want a^%d = %d", i, got[i], exp, want)
+		}
+	}
+}
+
+func TestRSRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{
+			name: "ISO 18004 annex I, 01234567 1-M",
+			data: []byte{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17},
+			want: []byte{165, 36, 212, 193, 237, 54, 199, 135, 44, 85},
+		},
+		{
+			name: "HELLO WORLD 1-M",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rsRemainder(tt.data, rsDivisor(len(tt.want))); !bytes.Equal(got, tt.want) {
+				t.Errorf("rsRemainder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr error
+	}{
+		{in: "", want: Medium},
+		{in: "L", want: Low},
+		{in: "m", want: Medium},
+		{in: "Q", want: Quartile},
+		{in: "h", want: High},
+		{in: "X", wantErr: ErrInvalidLevel},
+		{in: "LL", wantErr: ErrInvalidLevel},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseLevel(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	tests := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, Low, 19},
+		{1, Medium, 16},
+		{1, Quartile, 13},
+		{1, High, 9},
+		{7, Medium, 124},
+		{40, Low, 2956},
+		{40, High, 1276},
+	}
+
+	for _, tt := range tests {
+		if got := dataCodewords(tt.version, tt.level); got != tt.want {
+			t.Errorf("dataCodewords(%d, %v) = %d, want %d", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	// Byte mode capacities from ISO 18004 table 7.
+	tests := []struct {
+		length  int
+		level   Level
+		version int
+	}{
+		{17, Low, 1},
+		{18, Low, 2},
+		{14, Medium, 1},
+		{15, Medium, 2},
+		{11, Quartile, 1},
+		{7, High, 1},
+		{8, High, 2},
+		{2953, Low, 40},
+	}
+
+	for _, tt := range tests {
+		code, err := Encode(bytes.Repeat([]byte("a"), tt.length), tt.level)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes, %v) error = %v", tt.length, tt.level, err)
+		}
+
+		if code.Version != tt.version || code.Size != tt.version*4+17 {
+			t.Errorf("Encode(%d bytes, %v) = version %d size %d, want version %d", tt.length, tt.level, code.Version, code.Size, tt.version)
+		}
+	}
+
+	if _, err := Encode(bytes.Repeat([]byte("a"), 2954), Low); !errors.Is(err, ErrDataTooLong) {
+		t.Errorf("Encode(2954 bytes, L) error = %v, want %v", err, ErrDataTooLong)
+	}
+}
+
+func TestEncodeFinderPatterns(t *testing.T) {
+	code, err := Encode([]byte("https://example.com/abc"), Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+		for dy := range 7 {
+			for dx := range 7 {
+				ring := max(abs(dx-3), abs(dy-3))
+
+				if want := ring != 2; code.Dark(corner[0]+dx, corner[1]+dy) != want {
+					t.Fatalf("finder at %v: module (%d, %d) dark = %v, want %v", corner, dx, dy, !want, want)
+				}
+			}
+		}
+	}
+}
+
+func TestEncodeFormatInfo(t *testing.T) {
+	// Format information strings from ISO 18004 table C.1, masks 0 to 7.
+	valid := map[Level][]string{
+		Low: {
+			"111011111000100", "111001011110011", "111110110101010", "111100010011101",
+			"110011000101111", "110001100011000", "110110001000001", "110100101110110",
+		},
+		Medium: {
+			"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+			"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+		},
+		Quartile: {
+			"011010101011111", "011000001101000", "011111100110001", "011101000000110",
+			"010010010110100", "010000110000011", "010111011011010", "010101111101101",
+		},
+		High: {
+			"001011010001001", "001001110111110", "001110011100111", "001100111010000",
+			"000011101100010", "000001001010101", "000110100001100", "000100000111011",
+		},
+	}
+
+	for level, formats := range valid {
+		code, err := Encode([]byte("HELLO WORLD"), level)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var first, second int
+
+		for i := range 15 {
+			var x, y int
+
+			switch {
+			case i < 6:
+				x, y = 8, i
+			case i < 8:
+				x, y = 8, i+1
+			case i == 8:
+				x, y = 7, 8
+			default:
+				x, y = 14-i, 8
+			}
+
+			first |= bit(code.Dark(x, y)) << i
+
+			if i < 8 {
+				x, y = code.Size-1-i, 8
+			} else {
+				x, y = 8, code.Size-15+i
+			}
+
+			second |= bit(code.Dark(x, y)) << i
+		}
+
+		if first != second {
+			t.Errorf("level %v: format copies differ: %015b and %015b", level, first, second)
+		}
+
+		got := strconv.FormatInt(int64(first), 2)
+		got = strings.Repeat("0", 15-len(got)) + got
+
+		found := false
+
+		for _, f := range formats {
+			found = found || f == got
+		}
+
+		if !found {
+			t.Errorf("level %v: format %s is not a valid format string for the level", level, got)
+		}
+
+		if !code.Dark(8, code.Size-8) {
+			t.Errorf("level %v: dark module is light", level)
+		}
+	}
+}
+
+func TestEncodeVersionInfo(t *testing.T) {
+	// Version 7 information string from ISO 18004 table D.1.
+	const want = "000111110010010100"
+
+	code, err := Encode(bytes.Repeat([]byte("a"), 120), Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code.Version != 7 {
+		t.Fatalf("Encode() version = %d, want 7", code.Version)
+	}
+
+	var top, left int
+
+	for i := range 18 {
+		a, b := code.Size-11+i%3, i/3
+		top |= bit(code.Dark(a, b)) << i
+		left |= bit(code.Dark(b, a)) << i
+	}
+
+	for name, got := range map[string]int{"top right": top, "bottom left": left} {
+		s := strconv.FormatInt(int64(got), 2)
+		s = strings.Repeat("0", 18-len(s)) + s
+
+		if s != want {
+			t.Errorf("%s version info = %s, want %s", name, s, want)
+		}
+	}
+}
+
+func TestRenderSizeTooSmall(t *testing.T) {
+	code, err := Encode([]byte("a"), Low)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := code.PNG(&buf, code.Size+QuietZone*2-1); !errors.Is(err, ErrSizeTooSmall) {
+		t.Errorf("PNG() error = %v, want %v", err, ErrSizeTooSmall)
+	}
+
+	if err := code.SVG(&buf, code.Size+QuietZone*2-1); !errors.Is(err, ErrSizeTooSmall) {
+		t.Errorf("SVG() error = %v, want %v", err, ErrSizeTooSmall)
+	}
+}
+
+func alphaPow(n int) byte {
+	var out byte = 1
+
+	for range n {
+		out = gfMultiply(out, 0x02)
+	}
+
+	return out
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,51 @@
+package qrcode
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z byte
+
+	for i := 7; i >= 0; i-- {
+		carry := z >> 7
+		z = z<<1 ^ carry*0x1D
+		z ^= (y >> i & 1) * x
+	}
+
+	return z
+}
+
+func rsDivisor(degree int) []byte {
+	out := make([]byte, degree)
+	out[degree-1] = 1
+
+	var root byte = 1
+
+	for range degree {
+		for j := range out {
+			out[j] = gfMultiply(out[j], root)
+
+			if j+1 < len(out) {
+				out[j] ^= out[j+1]
+			}
+		}
+
+		root = gfMultiply(root, 0x02)
+	}
+
+	return out
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	out := make([]byte, len(divisor))
+
+	for _, b := range data {
+		factor := b ^ out[0]
+		copy(out, out[1:])
+		out[len(out)-1] = 0
+
+		for i := range out {
+			out[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+
+	return out
+}
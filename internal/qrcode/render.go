@@ -0,0 +1,111 @@
+package qrcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	QuietZone = 4
+)
+
+var (
+	ErrSizeTooSmall = errors.New("image size too small for qr code")
+)
+
+func (c *Code) Image(size int) (image.Image, error) {
+	const op = "internal/qrcode/Image"
+
+	scale := size / (c.Size + QuietZone*2)
+
+	if scale < 1 {
+		return nil, fmt.Errorf("%s: %w", op, ErrSizeTooSmall)
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	offset := (size - c.Size*scale) / 2
+
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.modules[y][x] {
+				continue
+			}
+
+			for dy := range scale {
+				row := img.Pix[(offset+y*scale+dy)*img.Stride:]
+
+				for dx := range scale {
+					row[offset+x*scale+dx] = 1
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
+
+func (c *Code) PNG(w io.Writer, size int) error {
+	const op = "internal/qrcode/PNG"
+
+	img, err := c.Image(size)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+
+	err = encoder.Encode(w, img)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *Code) SVG(w io.Writer, size int) error {
+	const op = "internal/qrcode/SVG"
+
+	if size < c.Size+QuietZone*2 {
+		return fmt.Errorf("%s: %w", op, ErrSizeTooSmall)
+	}
+
+	view := c.Size + QuietZone*2
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, view, view)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, view, view)
+
+	for y := range c.Size {
+		for x := 0; x < c.Size; x++ {
+			if !c.modules[y][x] {
+				continue
+			}
+
+			run := 1
+
+			for x+run < c.Size && c.modules[y][x+run] {
+				run++
+			}
+
+			fmt.Fprintf(buf, "M%d %dh%dv1h-%dz", x+QuietZone, y+QuietZone, run, run)
+			x += run - 1
+		}
+	}
+
+	fmt.Fprint(buf, `"/></svg>`)
+
+	err := buf.Flush()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
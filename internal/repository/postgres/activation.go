@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	updateActivationQuery = `UPDATE urls_alias SET active_from = $2, coming_soon = $3, updated_at = now()
+		WHERE alias = $1 AND ($2::timestamptz IS NULL OR expires_at IS NULL OR $2 < expires_at) RETURNING ` + urlColumns
+)
+
+func (conn Postgres) SetActivation(ctx context.Context, alias, ownerUUID string, activeFrom *time.Time, comingSoon bool) (url urls.URL, err error) {
+	const op = "internal/repository/postgres/activation.go/SetActivation"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err = setActivation(ctx, tx, alias, activeFrom, comingSoon)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
+
+func setActivation(ctx context.Context, tx pgx.Tx, alias string, activeFrom *time.Time, comingSoon bool) (urls.URL, error) {
+	const op = "internal/repository/postgres/activation.go/setActivation"
+
+	rows, err := tx.Query(ctx, updateActivationQuery, alias, activeFrom, comingSoon)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidActivation)
+		}
+
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	nextAliasSeqQuery = `SELECT nextval('urls_alias_seq')`
+	freeAliasesQuery  = `SELECT a FROM unnest($1::text[]) AS a WHERE NOT EXISTS (SELECT 1 FROM urls_alias WHERE alias = a)`
+)
+
+func (conn Postgres) NextAliasSeq(ctx context.Context) (int64, error) {
+	const op = "internal/repository/postgres/aliasseq.go/NextAliasSeq"
+
+	var value int64
+
+	err := conn.pool.QueryRow(ctx, nextAliasSeqQuery).Scan(&value)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return value, nil
+}
+
+func (conn Postgres) FreeAliases(ctx context.Context, aliases []string) ([]string, error) {
+	const op = "internal/repository/postgres/aliasseq.go/FreeAliases"
+
+	rows, err := conn.pool.Query(ctx, freeAliasesQuery, aliases)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowTo[string])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	lockClickedURLsQuery = `SELECT id FROM urls_alias WHERE id = ANY($1::bigint[]) FOR KEY SHARE`
+	addClicksHourlyQuery = `INSERT INTO clicks_hourly(url_id, bucket, count) SELECT * FROM unnest($1::bigint[], $2::timestamptz[], $3::bigint[])
+		ON CONFLICT (url_id, bucket) DO UPDATE SET count = clicks_hourly.count + EXCLUDED.count`
+	addClicksDimensionsQuery = `INSERT INTO clicks_daily_dimensions(url_id, day, dimension, value, count) SELECT * FROM unnest($1::bigint[], $2::date[], $3::text[], $4::text[], $5::bigint[])
+		ON CONFLICT (url_id, day, dimension, value) DO UPDATE SET count = clicks_daily_dimensions.count + EXCLUDED.count`
+
+	selectClicksSeriesQuery = `SELECT date_trunc($2, bucket, 'UTC') AS bucket, SUM(count)::bigint AS count FROM clicks_hourly
+		WHERE url_id = (SELECT id FROM urls_alias WHERE alias = $1) AND bucket >= $3 AND bucket < $4 GROUP BY 1 ORDER BY 1`
+	selectTopDimensionQuery = `SELECT value, SUM(count)::bigint AS count FROM clicks_daily_dimensions
+		WHERE url_id = (SELECT id FROM urls_alias WHERE alias = $1) AND dimension = $2 AND day >= ($3::timestamptz AT TIME ZONE 'UTC')::date AND day <= ($4::timestamptz AT TIME ZONE 'UTC')::date
+		GROUP BY value ORDER BY count DESC, value LIMIT $5`
+)
+
+var clicksColumns = []string{"url_id", "alias", "clicked_at", "referrer", "user_agent", "ip", "request_id", "country", "browser", "variant", "destination"}
+
+type hourlyKey struct {
+	urlID  int
+	bucket time.Time
+}
+
+type dimensionKey struct {
+	urlID     int
+	day       time.Time
+	dimension string
+	value     string
+}
+
+func (conn Postgres) SaveClicks(ctx context.Context, batch []clicks.Click) (err error) {
+	const op = "internal/repository/postgres/clicks.go/SaveClicks"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	batch, err = liveClicks(ctx, tx, batch)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"clicks"}, clicksColumns, pgx.CopyFromSlice(len(batch), func(i int) ([]any, error) {
+		click := batch[i]
+
+		return []any{click.URLID, click.Alias, click.ClickedAt, click.Referrer, click.UserAgent, click.IP, click.RequestID, click.Country, click.Browser, click.Variant, click.Destination}, nil
+	}))
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	counts := make(map[int]int64)
+
+	for _, click := range batch {
+		counts[click.URLID]++
+	}
+
+	ids := make([]int, 0, len(counts))
+	values := make([]int64, 0, len(counts))
+
+	for id, count := range counts {
+		ids = append(ids, id)
+		values = append(values, count)
+	}
+
+	_, err = tx.Exec(ctx, addClicksQuery, ids, values)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = saveClicksAggregates(ctx, tx, batch)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// liveClicks drops clicks whose URL has been purged since the redirect and
+// key-share locks the rest so they cannot be deleted before the batch commits.
+func liveClicks(ctx context.Context, tx pgx.Tx, batch []clicks.Click) ([]clicks.Click, error) {
+	const op = "internal/repository/postgres/clicks.go/liveClicks"
+
+	ids := make([]int, 0, len(batch))
+
+	for _, click := range batch {
+		ids = append(ids, click.URLID)
+	}
+
+	rows, err := tx.Query(ctx, lockClickedURLsQuery, ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	live, err := pgx.CollectRows(rows, pgx.RowTo[int])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return slices.DeleteFunc(batch, func(click clicks.Click) bool {
+		return !slices.Contains(live, click.URLID)
+	}), nil
+}
+
+func saveClicksAggregates(ctx context.Context, tx pgx.Tx, batch []clicks.Click) error {
+	const op = "internal/repository/postgres/clicks.go/saveClicksAggregates"
+
+	hourly := make(map[hourlyKey]int64)
+	dimensions := make(map[dimensionKey]int64)
+
+	for _, click := range batch {
+		at := click.ClickedAt.UTC()
+		day := at.Truncate(24 * time.Hour)
+
+		hourly[hourlyKey{urlID: click.URLID, bucket: at.Truncate(time.Hour)}]++
+
+		country := click.Country
+
+		if country == "" {
+			country = clicks.UnknownCountry
+		}
+
+		dimensions[dimensionKey{urlID: click.URLID, day: day, dimension: clicks.DimensionReferrer, value: click.ReferrerHost()}]++
+		dimensions[dimensionKey{urlID: click.URLID, day: day, dimension: clicks.DimensionBrowser, value: click.Browser}]++
+		dimensions[dimensionKey{urlID: click.URLID, day: day, dimension: clicks.DimensionCountry, value: country}]++
+
+		if click.Destination != "" {
+			dimensions[dimensionKey{urlID: click.URLID, day: day, dimension: clicks.DimensionVariant, value: click.Destination}]++
+		}
+	}
+
+	ids := make([]int, 0, len(hourly))
+	buckets := make([]time.Time, 0, len(hourly))
+	counts := make([]int64, 0, len(hourly))
+
+	for key, count := range hourly {
+		ids = append(ids, key.urlID)
+		buckets = append(buckets, key.bucket)
+		counts = append(counts, count)
+	}
+
+	_, err := tx.Exec(ctx, addClicksHourlyQuery, ids, buckets, counts)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids = make([]int, 0, len(dimensions))
+	days := make([]time.Time, 0, len(dimensions))
+	names := make([]string, 0, len(dimensions))
+	values := make([]string, 0, len(dimensions))
+	counts = make([]int64, 0, len(dimensions))
+
+	for key, count := range dimensions {
+		ids = append(ids, key.urlID)
+		days = append(days, key.day)
+		names = append(names, key.dimension)
+		values = append(values, key.value)
+		counts = append(counts, count)
+	}
+
+	_, err = tx.Exec(ctx, addClicksDimensionsQuery, ids, days, names, values, counts)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) ClicksSeries(ctx context.Context, alias, interval string, from, to time.Time) ([]clicks.Bucket, error) {
+	const op = "internal/repository/postgres/clicks.go/ClicksSeries"
+
+	rows, err := conn.pool.Query(ctx, selectClicksSeriesQuery, alias, interval, from, to)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[clicks.Bucket])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) TopClicksDimension(ctx context.Context, alias, dimension string, from, to time.Time, limit int) ([]clicks.Counter, error) {
+	const op = "internal/repository/postgres/clicks.go/TopClicksDimension"
+
+	rows, err := conn.pool.Query(ctx, selectTopDimensionQuery, alias, dimension, from, to, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[clicks.Counter])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	deleteDestinationsQuery = `DELETE FROM url_destinations WHERE url_id = (SELECT id FROM urls_alias WHERE alias = $1)`
+	insertDestinationsQuery = `INSERT INTO url_destinations(url_id, position, url, weight)
+		SELECT u.id, d.position - 1, d.url, d.weight FROM urls_alias u, unnest($2::text[], $3::int[]) WITH ORDINALITY AS d(url, weight, position) WHERE u.alias = $1`
+	updatePrimaryURLQuery = `UPDATE urls_alias SET url = $2, sticky_destinations = $3, updated_at = now() WHERE alias = $1`
+)
+
+func (conn Postgres) SetDestinations(ctx context.Context, alias, ownerUUID string, destinations []urls.Destination, sticky bool) (err error) {
+	const op = "internal/repository/postgres/destinations.go/SetDestinations"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, deleteDestinationsQuery, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(destinations) > 1 {
+		targets := make([]string, 0, len(destinations))
+		weights := make([]int32, 0, len(destinations))
+
+		for _, dest := range destinations {
+			targets = append(targets, dest.URL)
+			weights = append(weights, int32(dest.Weight))
+		}
+
+		_, err = tx.Exec(ctx, insertDestinationsQuery, alias, targets, weights)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, insertHistoryQuery, alias, urls.HistoryUpdate, ownerUUID, destinations[0].URL)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, updatePrimaryURLQuery, alias, destinations[0].URL, sticky)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,378 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolationCode = "23505"
+
+	insertTagsQuery      = `INSERT INTO tags(owner_uuid, name) SELECT $1::uuid, unnest($2::text[]) ON CONFLICT (owner_uuid, name) DO NOTHING`
+	deleteURLTagsQuery   = `DELETE FROM url_tags WHERE url_id = (SELECT id FROM urls_alias WHERE alias = $1)`
+	insertURLTagsQuery   = `INSERT INTO url_tags(url_id, tag_id) SELECT u.id, t.id FROM urls_alias u, tags t WHERE u.alias = $1 AND t.owner_uuid = $2 AND t.name = ANY($3::text[])`
+	clearURLFolderQuery  = `UPDATE urls_alias SET folder_id = NULL, updated_at = now() WHERE alias = $1`
+	updateURLFolderQuery = `UPDATE urls_alias AS u SET folder_id = f.id, updated_at = now() FROM folders f WHERE u.alias = $1 AND f.owner_uuid = $2 AND f.name = $3`
+)
+
+type groupQueries struct {
+	insert  string
+	list    string
+	rename  string
+	aliases string
+	delete  string
+
+	errNotFound error
+	errExists   error
+}
+
+var groupQueriesByKind = map[string]groupQueries{
+	urls.GroupTags: {
+		insert: `INSERT INTO tags(owner_uuid, name) VALUES($1, $2) ON CONFLICT (owner_uuid, name) DO NOTHING RETURNING id, name, 0::bigint AS links, created_at`,
+		list: `SELECT t.id, t.name, count(ut.url_id) AS links, t.created_at FROM tags t LEFT JOIN url_tags ut ON ut.tag_id = t.id
+			WHERE t.owner_uuid = $1 GROUP BY t.id ORDER BY t.name`,
+		rename: `UPDATE tags SET name = $3 WHERE id = $1 AND owner_uuid = $2
+			RETURNING id, name, (SELECT count(*) FROM url_tags WHERE tag_id = tags.id) AS links, created_at`,
+		aliases:     `SELECT u.alias FROM url_tags ut JOIN urls_alias u ON u.id = ut.url_id WHERE ut.tag_id = $1`,
+		delete:      `DELETE FROM tags WHERE id = $1 AND owner_uuid = $2`,
+		errNotFound: generalerrors.ErrTagNotFound,
+		errExists:   generalerrors.ErrTagAlreadyExists,
+	},
+	urls.GroupFolders: {
+		insert: `INSERT INTO folders(owner_uuid, name) VALUES($1, $2) ON CONFLICT (owner_uuid, name) DO NOTHING RETURNING id, name, 0::bigint AS links, created_at`,
+		list: `SELECT f.id, f.name, count(u.id) AS links, f.created_at FROM folders f LEFT JOIN urls_alias u ON u.folder_id = f.id
+			WHERE f.owner_uuid = $1 GROUP BY f.id ORDER BY f.name`,
+		rename: `UPDATE folders SET name = $3 WHERE id = $1 AND owner_uuid = $2
+			RETURNING id, name, (SELECT count(*) FROM urls_alias WHERE folder_id = folders.id) AS links, created_at`,
+		aliases:     `SELECT alias FROM urls_alias WHERE folder_id = $1`,
+		delete:      `DELETE FROM folders WHERE id = $1 AND owner_uuid = $2`,
+		errNotFound: generalerrors.ErrFolderNotFound,
+		errExists:   generalerrors.ErrFolderAlreadyExists,
+	},
+}
+
+func groupQueriesFor(kind string) (groupQueries, error) {
+	queries, ok := groupQueriesByKind[kind]
+
+	if !ok {
+		return groupQueries{}, generalerrors.ErrInvalidGroup
+	}
+
+	return queries, nil
+}
+
+func (conn Postgres) CreateGroup(ctx context.Context, kind, ownerUUID, name string) (urls.Group, error) {
+	const op = "internal/repository/postgres/groups.go/CreateGroup"
+
+	queries, err := groupQueriesFor(kind)
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := conn.pool.Query(ctx, queries.insert, ownerUUID, name)
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.Group])
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return urls.Group{}, fmt.Errorf("%s: %w", op, queries.errExists)
+		}
+
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
+
+func (conn Postgres) ListGroups(ctx context.Context, kind, ownerUUID string) ([]urls.Group, error) {
+	const op = "internal/repository/postgres/groups.go/ListGroups"
+
+	queries, err := groupQueriesFor(kind)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := conn.pool.Query(ctx, queries.list, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.Group])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) RenameGroup(ctx context.Context, kind, ownerUUID string, id int64, name string) (group urls.Group, aliases []string, err error) {
+	const op = "internal/repository/postgres/groups.go/RenameGroup"
+
+	queries, err := groupQueriesFor(kind)
+
+	if err != nil {
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	rows, err := tx.Query(ctx, queries.rename, id, ownerUUID, name)
+
+	if err != nil {
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.Group])
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return urls.Group{}, nil, fmt.Errorf("%s: %w", op, queries.errNotFound)
+		case errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode:
+			return urls.Group{}, nil, fmt.Errorf("%s: %w", op, queries.errExists)
+		}
+
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	aliases, err = groupAliases(ctx, tx, queries, id)
+
+	if err != nil {
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, aliases...)
+
+	if err != nil {
+		return urls.Group{}, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, aliases, nil
+}
+
+func (conn Postgres) DeleteGroup(ctx context.Context, kind, ownerUUID string, id int64) (aliases []string, err error) {
+	const op = "internal/repository/postgres/groups.go/DeleteGroup"
+
+	queries, err := groupQueriesFor(kind)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	aliases, err = groupAliases(ctx, tx, queries, id)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tag, err := tx.Exec(ctx, queries.delete, id, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() < 1 {
+		return nil, fmt.Errorf("%s: %w", op, queries.errNotFound)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, aliases...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return aliases, nil
+}
+
+func groupAliases(ctx context.Context, tx pgx.Tx, queries groupQueries, id int64) ([]string, error) {
+	const op = "internal/repository/postgres/groups.go/groupAliases"
+
+	rows, err := tx.Query(ctx, queries.aliases, id)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	aliases, err := pgx.CollectRows(rows, pgx.RowTo[string])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return aliases, nil
+}
+
+func (conn Postgres) SetURLTags(ctx context.Context, alias, ownerUUID string, tags []string) (err error) {
+	const op = "internal/repository/postgres/groups.go/SetURLTags"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, insertTagsQuery, ownerUUID, tags)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, deleteURLTagsQuery, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, insertURLTagsQuery, alias, ownerUUID, tags)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) SetURLFolder(ctx context.Context, alias, ownerUUID, folder string) (err error) {
+	const op = "internal/repository/postgres/groups.go/SetURLFolder"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if folder == "" {
+		_, err = tx.Exec(ctx, clearURLFolderQuery, alias)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil
+	}
+
+	tag, err := tx.Exec(ctx, updateURLFolderQuery, alias, ownerUUID, folder)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() < 1 {
+		return fmt.Errorf("%s: %w", op, generalerrors.ErrFolderNotFound)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
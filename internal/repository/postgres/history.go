@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+)
+
+// Versions are numbered per urls_alias row, so every writer must hold the row
+// lock (checkOwner, selectDeletedOwnerForLock or lockImportURLsQuery) in a
+// read committed transaction before inserting: the max+1 subquery then sees
+// the version committed by whoever held the lock before.
+const (
+	nextVersion = `COALESCE((SELECT max(h.version) FROM urls_alias_history h WHERE h.url_id = u.id), 0) + 1`
+
+	insertHistoryQuery = `INSERT INTO urls_alias_history(url_id, version, action, changed_by, old_url, new_url)
+		SELECT u.id, ` + nextVersion + `, $2, NULLIF($3, '')::uuid, u.url, $4 FROM urls_alias u WHERE u.alias = $1`
+	lockImportURLsQuery = `SELECT u.id FROM urls_alias u JOIN import_urls i ON i.alias = u.alias
+		WHERE u.owner_uuid = $1::uuid AND u.deleted_at IS NULL ORDER BY u.id FOR UPDATE OF u`
+	insertImportHistoryQuery = `INSERT INTO urls_alias_history(url_id, version, action, changed_by, old_url, new_url)
+		SELECT u.id, ` + nextVersion + `, $2, $1::uuid, u.url, i.url FROM urls_alias u
+		JOIN (SELECT DISTINCT ON (alias) alias, url FROM import_urls ORDER BY alias, line DESC) i ON i.alias = u.alias
+		WHERE u.owner_uuid = $1::uuid AND u.deleted_at IS NULL AND u.url <> i.url`
+	listHistoryQuery = `SELECT h.version, h.action, COALESCE(h.changed_by::text, '') AS changed_by, h.old_url, h.new_url, h.changed_at
+		FROM urls_alias_history h JOIN urls_alias u ON u.id = h.url_id WHERE u.alias = $1 AND u.owner_uuid = $2 ORDER BY h.version DESC LIMIT $3`
+	selectVersionURLQuery = `SELECT h.old_url FROM urls_alias_history h JOIN urls_alias u ON u.id = h.url_id
+		WHERE u.alias = $1 AND h.version = $2 AND u.owner_uuid = $3`
+)
+
+func (conn Postgres) ListHistory(ctx context.Context, alias, ownerUUID string, limit int) ([]urls.Version, error) {
+	const op = "internal/repository/postgres/history.go/ListHistory"
+
+	rows, err := conn.pool.Query(ctx, listHistoryQuery, alias, ownerUUID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.Version])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) VersionURL(ctx context.Context, alias, ownerUUID string, version int) (string, error) {
+	const op = "internal/repository/postgres/history.go/VersionURL"
+
+	var target string
+
+	err := conn.pool.QueryRow(ctx, selectVersionURLQuery, alias, version, ownerUUID).Scan(&target)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, generalerrors.ErrVersionNotFound)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return target, nil
+}
+
+func (conn Postgres) RollbackURL(ctx context.Context, alias, ownerUUID string, version int) (url urls.URL, err error) {
+	const op = "internal/repository/postgres/history.go/RollbackURL"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var target string
+
+	err = tx.QueryRow(ctx, selectVersionURLQuery, alias, version, ownerUUID).Scan(&target)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrVersionNotFound)
+		}
+
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err = updateURL(ctx, tx, target, alias, ownerUUID, urls.HistoryRollback)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
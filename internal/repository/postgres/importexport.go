@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	createImportTableQuery = `CREATE TEMP TABLE import_urls(line BIGINT NOT NULL, url TEXT NOT NULL, alias TEXT NOT NULL,
+		redirect_code INT NOT NULL, expires_at TIMESTAMPTZ, max_clicks INT) ON COMMIT DROP`
+	countImportAliasesQuery = `SELECT count(DISTINCT alias) FROM import_urls`
+
+	importInsert = `INSERT INTO urls_alias(url, alias, redirect_code, owner_uuid, expires_at, max_clicks, remaining_clicks)
+		SELECT DISTINCT ON (alias) url, alias, redirect_code, NULLIF($1, '')::uuid, expires_at, max_clicks, max_clicks FROM import_urls ORDER BY alias, line DESC`
+
+	importSkipQuery = `WITH changed AS (` + importInsert + ` ON CONFLICT (alias) DO NOTHING RETURNING alias, url, owner_uuid),
+		events AS (` + insertChangedEventsQuery + `) SELECT count(*) FROM changed`
+	importOverwriteQuery = importInsert + ` ON CONFLICT (alias) DO UPDATE SET url = EXCLUDED.url, redirect_code = EXCLUDED.redirect_code,
+		expires_at = EXCLUDED.expires_at, max_clicks = EXCLUDED.max_clicks, remaining_clicks = EXCLUDED.remaining_clicks, updated_at = now()
+		WHERE urls_alias.owner_uuid = EXCLUDED.owner_uuid AND urls_alias.deleted_at IS NULL RETURNING alias, xmax = 0 AS inserted`
+
+	exportURLsQuery = `SELECT ` + urlColumns + ` FROM urls_alias WHERE owner_uuid = $1 AND deleted_at IS NULL ORDER BY id`
+)
+
+var importColumns = []string{"line", "url", "alias", "redirect_code", "expires_at", "max_clicks"}
+
+type importCopySource struct {
+	source urls.ImportSource
+	line   int64
+}
+
+func (s *importCopySource) Next() bool {
+	s.line++
+
+	return s.source.Next()
+}
+
+func (s *importCopySource) Values() ([]any, error) {
+	url := s.source.URL()
+
+	return []any{s.line, url.URL, url.Alias, url.RedirectCode, url.ExpiresAt, url.MaxClicks}, nil
+}
+
+func (s *importCopySource) Err() error {
+	return s.source.Err()
+}
+
+func (conn Postgres) ImportURLs(ctx context.Context, ownerUUID string, source urls.ImportSource, policy string) (result urls.ImportResult, err error) {
+	const op = "internal/repository/postgres/importexport.go/ImportURLs"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, createImportTableQuery)
+
+	if err != nil {
+		return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result.Total, err = tx.CopyFrom(ctx, pgx.Identifier{"import_urls"}, importColumns, &importCopySource{source: source})
+
+	if source.Err() != nil {
+		err = source.Err()
+	}
+
+	if err != nil {
+		return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch policy {
+	case urls.ImportOverwrite:
+		_, err = tx.Exec(ctx, lockImportURLsQuery, ownerUUID)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.Exec(ctx, insertImportHistoryQuery, ownerUUID, urls.HistoryUpdate)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rows, err := tx.Query(ctx, importOverwriteQuery, ownerUUID)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var (
+			alias    string
+			inserted bool
+			created  []string
+		)
+
+		_, err = pgx.ForEachRow(rows, []any{&alias, &inserted}, func() error {
+			if inserted {
+				result.Inserted++
+				created = append(created, alias)
+			} else {
+				result.Updated++
+				result.UpdatedAliases = append(result.UpdatedAliases, alias)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = writeURLEvents(ctx, tx, outbox.EventURLCreated, created...)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, result.UpdatedAliases...)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+	case urls.ImportFail:
+		var distinct int64
+
+		err = tx.QueryRow(ctx, countImportAliasesQuery).Scan(&distinct)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = tx.QueryRow(ctx, importSkipQuery, ownerUUID, outbox.EventURLCreated).Scan(&result.Inserted)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if result.Inserted < distinct {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasAlreadyExists)
+		}
+	default:
+		err = tx.QueryRow(ctx, importSkipQuery, ownerUUID, outbox.EventURLCreated).Scan(&result.Inserted)
+
+		if err != nil {
+			return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	result.Skipped = result.Total - result.Inserted - result.Updated
+
+	return result, nil
+}
+
+func (conn Postgres) ExportURLs(ctx context.Context, ownerUUID string, fn func(url urls.URL) error) error {
+	const op = "internal/repository/postgres/importexport.go/ExportURLs"
+
+	rows, err := conn.pool.Query(ctx, exportURLsQuery, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		url, err := pgx.RowToStructByName[urls.URL](rows)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = fn(url)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	urlEventPayload = `jsonb_build_object('alias', u.alias, 'url', u.url, 'owner_uuid', u.owner_uuid)`
+
+	insertURLEventsQuery = `INSERT INTO outbox(aggregate, aggregate_id, event, payload)
+		SELECT 'url', u.alias, $2, ` + urlEventPayload + ` FROM urls_alias u WHERE u.alias = ANY($1::text[])`
+	insertChangedEventsQuery = `INSERT INTO outbox(aggregate, aggregate_id, event, payload)
+		SELECT 'url', u.alias, $2, ` + urlEventPayload + ` FROM changed u`
+	insertUserEventQuery = `INSERT INTO outbox(aggregate, aggregate_id, event, payload)
+		SELECT 'user', u.uuid::text, $2, jsonb_build_object('uuid', u.uuid, 'username', u.username, 'blocked', u.user_blocked) FROM users u WHERE u.uuid = $1::uuid`
+
+	claimOutboxQuery = `UPDATE outbox SET attempts = attempts + 1, next_attempt_at = now() + $2::interval
+		WHERE id IN (SELECT id FROM outbox WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at <= now() ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED)
+		RETURNING id, event_id::text AS event_id, aggregate, aggregate_id, event, payload, attempts, created_at`
+	markOutboxPublishedQuery = `UPDATE outbox SET published_at = now(), last_error = '' WHERE id = ANY($1::bigint[])`
+	markOutboxFailedQuery    = `UPDATE outbox SET next_attempt_at = now() + $2::interval, last_error = $3, dead_at = CASE WHEN $4 THEN now() END WHERE id = $1`
+	purgeOutboxQuery         = `DELETE FROM outbox WHERE published_at <= now() - $1::interval OR dead_at <= now() - $1::interval`
+)
+
+func writeURLEvents(ctx context.Context, tx pgx.Tx, event string, aliases ...string) error {
+	const op = "internal/repository/postgres/outbox.go/writeURLEvents"
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, insertURLEventsQuery, aliases, event)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func writeUserEvent(ctx context.Context, tx pgx.Tx, event, uuid string) error {
+	const op = "internal/repository/postgres/outbox.go/writeUserEvent"
+
+	_, err := tx.Exec(ctx, insertUserEventQuery, uuid, event)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]outbox.Event, error) {
+	const op = "internal/repository/postgres/outbox.go/ClaimOutbox"
+
+	rows, err := conn.pool.Query(ctx, claimOutboxQuery, limit, lease)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[outbox.Event])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	slices.SortFunc(out, func(a, b outbox.Event) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return out, nil
+}
+
+func (conn Postgres) MarkOutboxPublished(ctx context.Context, ids []int64) error {
+	const op = "internal/repository/postgres/outbox.go/MarkOutboxPublished"
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := conn.pool.Exec(ctx, markOutboxPublishedQuery, ids)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) MarkOutboxFailed(ctx context.Context, id int64, retryIn time.Duration, dead bool, reason string) error {
+	const op = "internal/repository/postgres/outbox.go/MarkOutboxFailed"
+
+	_, err := conn.pool.Exec(ctx, markOutboxFailedQuery, id, retryIn, reason, dead)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) PurgeOutbox(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "internal/repository/postgres/outbox.go/PurgeOutbox"
+
+	tag, err := conn.pool.Exec(ctx, purgeOutboxQuery, retention)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	addPopularAliasesQuery = `INSERT INTO popular_aliases(url_id, bucket, count) SELECT u.id, $3, c.count FROM unnest($1::text[], $2::bigint[]) AS c(alias, count)
+		JOIN urls_alias u ON u.alias = c.alias AND u.deleted_at IS NULL ON CONFLICT (url_id, bucket) DO UPDATE SET count = popular_aliases.count + EXCLUDED.count`
+	selectPopularAliasesQuery = `SELECT u.alias, SUM(p.count)::bigint AS count FROM popular_aliases p JOIN urls_alias u ON u.id = p.url_id
+		WHERE p.bucket >= $1 AND u.owner_uuid = $3 AND u.deleted_at IS NULL GROUP BY u.alias ORDER BY count DESC, u.alias LIMIT $2`
+	prunePopularAliasesQuery = `DELETE FROM popular_aliases WHERE bucket < $1`
+)
+
+func (conn Postgres) SavePopularAliases(ctx context.Context, bucket time.Time, aliases []urls.PopularAlias) error {
+	const op = "internal/repository/postgres/popular.go/SavePopularAliases"
+
+	names := make([]string, 0, len(aliases))
+	counts := make([]int64, 0, len(aliases))
+
+	for _, alias := range aliases {
+		names = append(names, alias.Alias)
+		counts = append(counts, alias.Count)
+	}
+
+	_, err := conn.pool.Exec(ctx, addPopularAliasesQuery, names, counts, bucket)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) PopularAliases(ctx context.Context, ownerUUID string, since time.Time, limit int) ([]urls.PopularAlias, error) {
+	const op = "internal/repository/postgres/popular.go/PopularAliases"
+
+	rows, err := conn.pool.Query(ctx, selectPopularAliasesQuery, since, limit, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.PopularAlias])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) PrunePopularAliases(ctx context.Context, before time.Time) (int64, error) {
+	const op = "internal/repository/postgres/popular.go/PrunePopularAliases"
+
+	tag, err := conn.pool.Exec(ctx, prunePopularAliasesQuery, before)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
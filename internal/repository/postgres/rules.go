@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	deleteRulesQuery = `DELETE FROM url_rules WHERE url_id = (SELECT id FROM urls_alias WHERE alias = $1)`
+	insertRulesQuery = `INSERT INTO url_rules(url_id, position, os, device, language, country, url)
+		SELECT u.id, r.position - 1, r.os, r.device, r.language, r.country, r.url FROM urls_alias u,
+		unnest($2::text[], $3::text[], $4::text[], $5::text[], $6::text[]) WITH ORDINALITY AS r(os, device, language, country, url, position) WHERE u.alias = $1`
+	touchURLQuery = `UPDATE urls_alias SET updated_at = now() WHERE alias = $1`
+)
+
+func (conn Postgres) SetRules(ctx context.Context, alias, ownerUUID string, rules []urls.Rule) (err error) {
+	const op = "internal/repository/postgres/rules.go/SetRules"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, deleteRulesQuery, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(rules) > 0 {
+		systems := make([]string, 0, len(rules))
+		devices := make([]string, 0, len(rules))
+		languages := make([]string, 0, len(rules))
+		countries := make([]string, 0, len(rules))
+		targets := make([]string, 0, len(rules))
+
+		for _, rule := range rules {
+			systems = append(systems, rule.OS)
+			devices = append(devices, rule.Device)
+			languages = append(languages, rule.Language)
+			countries = append(countries, rule.Country)
+			targets = append(targets, rule.URL)
+		}
+
+		_, err = tx.Exec(ctx, insertRulesQuery, alias, systems, devices, languages, countries, targets)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, touchURLQuery, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
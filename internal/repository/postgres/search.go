@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	searchTagMatch = `EXISTS (SELECT 1 FROM url_tags ut JOIN tags t ON t.id = ut.tag_id WHERE ut.url_id = urls_alias.id AND to_tsvector('simple', t.name) @@ query)`
+
+	searchURLsQuery = `SELECT ` + urlColumns + ` FROM urls_alias, to_tsquery('simple', $2) AS query
+		WHERE owner_uuid = $1 AND deleted_at IS NULL AND (search_vector @@ query OR ` + searchTagMatch + `)
+		ORDER BY ts_rank(search_vector, query) + CASE WHEN ` + searchTagMatch + ` THEN 0.5 ELSE 0 END DESC, id DESC
+		LIMIT $3 OFFSET $4`
+)
+
+func (conn Postgres) SearchURLs(ctx context.Context, filter urls.SearchFilter) ([]urls.URL, error) {
+	const op = "internal/repository/postgres/search.go/SearchURLs"
+
+	rows, err := conn.pool.Query(ctx, searchURLsQuery, filter.OwnerUUID, filter.Query, filter.Limit, filter.Offset)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
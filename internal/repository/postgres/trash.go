@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	listTrashQuery            = `SELECT ` + urlColumns + ` FROM urls_alias WHERE owner_uuid = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC LIMIT $2`
+	selectDeletedOwnerForLock = `SELECT COALESCE(owner_uuid::text, '') FROM urls_alias WHERE alias = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+	restoreURLQuery           = `UPDATE urls_alias SET deleted_at = NULL, updated_at = now() WHERE alias = $1 RETURNING ` + urlColumns
+	purgeDeletedQuery         = `WITH changed AS (DELETE FROM urls_alias WHERE deleted_at <= now() - $1::interval RETURNING alias, url, owner_uuid) ` + insertChangedEventsQuery
+)
+
+func (conn Postgres) ListTrash(ctx context.Context, ownerUUID string, limit int) ([]urls.URL, error) {
+	const op = "internal/repository/postgres/trash.go/ListTrash"
+
+	rows, err := conn.pool.Query(ctx, listTrashQuery, ownerUUID, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) RestoreURL(ctx context.Context, alias, ownerUUID string) (url urls.URL, err error) {
+	const op = "internal/repository/postgres/trash.go/RestoreURL"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
+		}
+	}()
+
+	var owner string
+
+	err = tx.QueryRow(ctx, selectDeletedOwnerForLock, alias).Scan(&owner)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+		}
+
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if owner == "" || owner != ownerUUID {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
+
+	_, err = tx.Exec(ctx, insertHistoryQuery, alias, urls.HistoryRestore, ownerUUID, "")
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, restoreURLQuery, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLRestored, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
+
+func (conn Postgres) PurgeDeleted(ctx context.Context, quarantine time.Duration) (int64, error) {
+	const op = "internal/repository/postgres/trash.go/PurgeDeleted"
+
+	tag, err := conn.pool.Exec(ctx, purgeDeletedQuery, quarantine, outbox.EventURLPurged)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
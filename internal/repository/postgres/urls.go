@@ -4,21 +4,54 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
 	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/jackc/pgx/v5"
 )
 
 const (
-	insertAliasQuery    = `INSERT INTO urls_alias(url, alias) VALUES($1, $2) RETURNING ID`
-	selectURLItemQuery  = `SELECT ID, url, alias FROM urls_alias WHERE alias = $1`
-	deleteURLQuery      = `DELETE FROM urls_alias WHERE alias = $1`
-	updateURLQuery      = `UPDATE urls_alias SET url = $1 WHERE alias = $2 RETURNING url`
-	insertPopAliasQuery = `INSERT INTO most_popular_aliasses(alias, count_of_req) VALUES($1, $2)`
+	urlColumns = `id, url, alias, title, redirect_code, COALESCE(owner_uuid::text, '') AS owner_uuid,
+		created_at, updated_at, clicks, expires_at, max_clicks, remaining_clicks,
+		password_hash IS NOT NULL AS protected, COALESCE(password_hash, '') AS password_hash,
+		COALESCE((SELECT f.name FROM folders f WHERE f.id = urls_alias.folder_id), '') AS folder,
+		ARRAY(SELECT t.name FROM url_tags ut JOIN tags t ON t.id = ut.tag_id WHERE ut.url_id = urls_alias.id ORDER BY t.name) AS tags,
+		COALESCE((SELECT jsonb_agg(jsonb_build_object('url', d.url, 'weight', d.weight) ORDER BY d.position) FROM url_destinations d WHERE d.url_id = urls_alias.id), '[]') AS destinations,
+		sticky_destinations AS sticky, deleted_at, active_from, coming_soon,
+		COALESCE((SELECT jsonb_agg(jsonb_build_object('os', r.os, 'device', r.device, 'language', r.language, 'country', r.country, 'url', r.url) ORDER BY r.position)
+			FROM url_rules r WHERE r.url_id = urls_alias.id), '[]') AS rules`
+
+	insertAlias = `INSERT INTO urls_alias(url, alias, redirect_code, owner_uuid, expires_at, max_clicks, remaining_clicks, password_hash, title, active_from, coming_soon)
+		VALUES($1, $2, $3, NULLIF($4, '')::uuid, $5, $6, $6, NULLIF($7, ''), $8, $9, $10)`
+	insertAliasIfAbsentQuery = insertAlias + ` ON CONFLICT (alias) DO NOTHING RETURNING id`
+	selectURLItemQuery       = `SELECT ` + urlColumns + ` FROM urls_alias WHERE alias = $1 AND deleted_at IS NULL`
+	selectURLOwnerForLock    = `SELECT COALESCE(owner_uuid::text, '') FROM urls_alias WHERE alias = $1 AND deleted_at IS NULL FOR UPDATE`
+	deleteURLQuery           = `UPDATE urls_alias SET deleted_at = now(), updated_at = now() WHERE alias = $1 AND deleted_at IS NULL`
+	updateURLQuery           = `UPDATE urls_alias SET url = $1, updated_at = now() WHERE alias = $2 RETURNING ` + urlColumns
+	updatePrimaryDestination = `UPDATE url_destinations SET url = $1 WHERE position = 0 AND url_id = (SELECT id FROM urls_alias WHERE alias = $2)`
+	consumeClickQuery        = `UPDATE urls_alias SET remaining_clicks = remaining_clicks - 1 WHERE alias = $1 AND remaining_clicks > 0 RETURNING remaining_clicks`
+	peekClickQuery           = `SELECT remaining_clicks FROM urls_alias WHERE alias = $1 AND remaining_clicks > 0`
+	addClicksQuery           = `UPDATE urls_alias AS u SET clicks = u.clicks + c.count FROM unnest($1::bigint[], $2::bigint[]) AS c(id, count) WHERE u.id = c.id`
+
+	purgeExpiredQuery   = `WITH changed AS (DELETE FROM urls_alias WHERE expires_at <= now() - $1::interval RETURNING alias, url, owner_uuid) ` + insertChangedEventsQuery
+	archiveExpiredQuery = `WITH changed AS (DELETE FROM urls_alias WHERE expires_at <= now() - $1::interval RETURNING id, url, alias, owner_uuid, created_at, expires_at),
+		archived AS (INSERT INTO urls_alias_archive(id, url, alias, owner_uuid, created_at, expires_at) SELECT id, url, alias, owner_uuid, created_at, expires_at FROM changed
+		ON CONFLICT (id) DO NOTHING) ` + insertChangedEventsQuery
+
+	listURLsFilter = ` FROM urls_alias WHERE owner_uuid = $1 AND deleted_at IS NULL AND ($2 = '' OR alias ILIKE '%' || $2 || '%' OR url ILIKE '%' || $2 || '%')
+		AND ($5 = '' OR folder_id = (SELECT f.id FROM folders f WHERE f.owner_uuid = $1 AND f.name = $5))
+		AND ($6 = '' OR EXISTS (SELECT 1 FROM url_tags ut JOIN tags t ON t.id = ut.tag_id WHERE ut.url_id = urls_alias.id AND t.owner_uuid = $1 AND t.name = $6))`
+
+	listURLsByCreatedQuery = `SELECT ` + urlColumns + listURLsFilter + ` AND ($3 = 0 OR id < $3) ORDER BY id DESC LIMIT $4`
+	listURLsByClicksQuery  = `SELECT ` + urlColumns + listURLsFilter + ` AND ($3 = 0 OR (clicks, id) < ($7, $3)) ORDER BY clicks DESC, id DESC LIMIT $4`
 )
 
-func (conn Postgres) SaveAlias(ctx context.Context, url, alias string) (id int, err error) {
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (conn Postgres) SaveAlias(ctx context.Context, url urls.URL) (id int, err error) {
 	const op = "repository/postgres/SaveAlias"
 	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: ReadWriteAccessMode})
 
@@ -40,35 +73,179 @@ func (conn Postgres) SaveAlias(ctx context.Context, url, alias string) (id int,
 		}
 	}()
 
-	rows, err := tx.Query(ctx, insertAliasQuery, url, alias)
+	err = tx.QueryRow(ctx, insertAliasIfAbsentQuery, url.URL, url.Alias, url.RedirectCode, url.OwnerUUID, url.ExpiresAt, url.MaxClicks, url.PasswordHash, url.Title, url.ActiveFrom, url.ComingSoon).Scan(&id)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return -1, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasAlreadyExists)
+		}
+
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
-	for rows.Next() {
-		err = rows.Scan(&id)
+	err = writeURLEvents(ctx, tx, outbox.EventURLCreated, url.Alias)
+
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (conn Postgres) SaveAliases(ctx context.Context, batch []urls.URL) (out []urls.SaveResult, err error) {
+	const op = "internal/repository/postgres/urls.go/SaveAliases"
+
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
 
 		if err != nil {
-			return -1, fmt.Errorf("%s: %w", op, err)
+			e = tx.Rollback(ctx)
+		} else {
+			e = tx.Commit(ctx)
+		}
+
+		if err == nil && e != nil {
+			err = fmt.Errorf("%s:finishing transaction: %w", op, e)
 		}
+	}()
+
+	out, err = insertAliasesPipelined(ctx, tx, batch)
+
+	if err != nil {
+		out, err = insertAliasesOneByOne(ctx, tx, batch)
 	}
 
-	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	created := make([]string, 0, len(batch))
 
-	if rows.CommandTag().RowsAffected() < 1 {
-		return -1, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasAlreadyExists)
+	for _, result := range out {
+		if result.Err == nil {
+			created = append(created, result.Alias)
+		}
 	}
 
-	return id, nil
+	err = writeURLEvents(ctx, tx, outbox.EventURLCreated, created...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+// insertAliasesPipelined sends the whole batch in one round trip inside a
+// savepoint. Any error other than an alias conflict aborts it, and the caller
+// falls back to insertAliasesOneByOne to find out which items failed.
+func insertAliasesPipelined(ctx context.Context, tx pgx.Tx, batch []urls.URL) (out []urls.SaveResult, err error) {
+	const op = "internal/repository/postgres/urls.go/insertAliasesPipelined"
+
+	sp, err := tx.Begin(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer func() {
+		var e error
+
+		if err != nil {
+			e = sp.Rollback(ctx)
+		} else {
+			e = sp.Commit(ctx)
+		}
+
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("%s:finishing savepoint: %w", op, e))
+		}
+	}()
+
+	queue := &pgx.Batch{}
+
+	for _, url := range batch {
+		queue.Queue(insertAliasIfAbsentQuery, url.URL, url.Alias, url.RedirectCode, url.OwnerUUID, url.ExpiresAt, url.MaxClicks, url.PasswordHash, url.Title, url.ActiveFrom, url.ComingSoon)
+	}
+
+	results := sp.SendBatch(ctx, queue)
+	out = make([]urls.SaveResult, len(batch))
+
+	for i, url := range batch {
+		out[i] = scanSaveResult(results.QueryRow(), url.Alias)
+
+		if out[i].Err != nil && !errors.Is(out[i].Err, generalerrors.ErrAliasAlreadyExists) {
+			results.Close()
+
+			return nil, fmt.Errorf("%s: %w", op, out[i].Err)
+		}
+	}
+
+	err = results.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func insertAliasesOneByOne(ctx context.Context, tx pgx.Tx, batch []urls.URL) ([]urls.SaveResult, error) {
+	const op = "internal/repository/postgres/urls.go/insertAliasesOneByOne"
+
+	out := make([]urls.SaveResult, len(batch))
+
+	for i, url := range batch {
+		sp, err := tx.Begin(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		out[i] = scanSaveResult(sp.QueryRow(ctx, insertAliasIfAbsentQuery, url.URL, url.Alias, url.RedirectCode, url.OwnerUUID, url.ExpiresAt, url.MaxClicks, url.PasswordHash, url.Title, url.ActiveFrom, url.ComingSoon), url.Alias)
+
+		if out[i].Err != nil && !errors.Is(out[i].Err, generalerrors.ErrAliasAlreadyExists) {
+			err = sp.Rollback(ctx)
+		} else {
+			err = sp.Commit(ctx)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return out, nil
 }
 
-func (conn Postgres) GetURL(ctx context.Context, alias string) (url string, err error) {
+func scanSaveResult(row pgx.Row, alias string) urls.SaveResult {
+	var id int
+
+	err := row.Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return urls.SaveResult{ID: -1, Alias: alias, Err: generalerrors.ErrAliasAlreadyExists}
+	}
+
+	if err != nil {
+		return urls.SaveResult{ID: -1, Alias: alias, Err: err}
+	}
+
+	return urls.SaveResult{ID: id, Alias: alias}
+}
+
+func (conn Postgres) GetURL(ctx context.Context, alias string) (url urls.URL, err error) {
 	const op = "repo/postgresql/postgres.go.GetURL"
 	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadOnlyAccessMode})
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	defer func() {
@@ -89,28 +266,28 @@ func (conn Postgres) GetURL(ctx context.Context, alias string) (url string, err
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
 		}
 
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	urlItem, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.URL])
+	url, err = pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.URL])
 
 	if err != nil {
 		if err.Error() == NoRowsInCollectedSet {
-			return "", fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
 		}
 
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return urlItem.URL, nil
+	return url, nil
 }
 
-func (conn Postgres) DeleteURL(ctx context.Context, alias string) (err error) {
+func (conn Postgres) DeleteURL(ctx context.Context, alias, ownerUUID string) (err error) {
 	const op = "repo/postgresql/postgres.go.DeleteURL"
-	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: ReadWriteAccessMode})
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -130,6 +307,18 @@ func (conn Postgres) DeleteURL(ctx context.Context, alias string) (err error) {
 		}
 	}()
 
+	err = checkOwner(ctx, tx, alias, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, insertHistoryQuery, alias, urls.HistoryDelete, ownerUUID, "")
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	rows, err := tx.Query(ctx, deleteURLQuery, alias)
 
 	if err != nil {
@@ -138,15 +327,23 @@ func (conn Postgres) DeleteURL(ctx context.Context, alias string) (err error) {
 
 	rows.Close()
 
+	err = writeURLEvents(ctx, tx, outbox.EventURLDeleted, alias)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
-func (conn Postgres) UpdateURL(ctx context.Context, newURL, alias string) (url string, err error) {
-	const op = "repo/postgresql/postgres.go.DeleteURL"
-	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: ReadWriteAccessMode})
+// UpdateURL changes the destination, the activation window or both in one
+// transaction, so an invalid activation does not leave a half-applied update.
+func (conn Postgres) UpdateURL(ctx context.Context, newURL, alias, ownerUUID string, activation *urls.Activation) (url urls.URL, err error) {
+	const op = "repo/postgresql/postgres.go.UpdateURL"
+	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.ReadCommitted, AccessMode: ReadWriteAccessMode})
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	defer func() {
@@ -163,38 +360,169 @@ func (conn Postgres) UpdateURL(ctx context.Context, newURL, alias string) (url s
 		}
 	}()
 
-	rows, err := tx.Query(ctx, updateURLQuery, newURL, alias)
+	err = checkOwner(ctx, tx, alias, ownerUUID)
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	for rows.Next() {
-		err = rows.Scan(&url)
+	if newURL != "" {
+		url, err = updateURL(ctx, tx, newURL, alias, ownerUUID, urls.HistoryUpdate)
 
 		if err != nil {
-			return "", fmt.Errorf("%s: %w", op, err)
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	rows.Close()
+	if activation != nil {
+		url, err = setActivation(ctx, tx, alias, activation.ActiveFrom, activation.ComingSoon)
+
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return url, nil
+}
+
+func updateURL(ctx context.Context, tx pgx.Tx, newURL, alias, ownerUUID, action string) (urls.URL, error) {
+	const op = "internal/repository/postgres/urls.go/updateURL"
+
+	_, err := tx.Exec(ctx, insertHistoryQuery, alias, action, ownerUUID, newURL)
 
-	if rows.CommandTag().RowsAffected() < 1 {
-		return "", fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.Exec(ctx, updatePrimaryDestination, newURL, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := tx.Query(ctx, updateURLQuery, newURL, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+		}
+
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = writeURLEvents(ctx, tx, outbox.EventURLUpdated, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return url, nil
 }
 
-func (p Postgres) SendPopAlias(ctx context.Context, alias string, countOfReq int) error {
-	const op = "internal/repository/postgres/urls.go/SendPopAlias"
+func checkOwner(ctx context.Context, tx pgx.Tx, alias, ownerUUID string) error {
+	const op = "internal/repository/postgres/urls.go/checkOwner"
+
+	var owner string
 
-	rows, err := p.pool.Query(ctx, insertPopAliasQuery, alias, countOfReq)
+	err := tx.QueryRow(ctx, selectURLOwnerForLock, alias).Scan(&owner)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotFound)
+		}
+
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	rows.Close()
+
+	if owner == "" || owner != ownerUUID {
+		return fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
 
 	return nil
 }
+
+func (conn Postgres) ListURLs(ctx context.Context, filter urls.ListFilter) ([]urls.URL, error) {
+	const op = "internal/repository/postgres/urls.go/ListURLs"
+
+	query := listURLsByCreatedQuery
+	args := []any{filter.OwnerUUID, likeEscaper.Replace(filter.Query), filter.AfterID, filter.Limit, filter.Folder, filter.Tag}
+
+	if filter.SortBy == urls.SortByClicks {
+		query = listURLsByClicksQuery
+		args = append(args, filter.AfterClicks)
+	}
+
+	rows, err := conn.pool.Query(ctx, query, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[urls.URL])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) PurgeExpired(ctx context.Context, grace time.Duration, archive bool) (int64, error) {
+	const op = "internal/repository/postgres/urls.go/PurgeExpired"
+
+	query := purgeExpiredQuery
+
+	if archive {
+		query = archiveExpiredQuery
+	}
+
+	tag, err := conn.pool.Exec(ctx, query, grace, outbox.EventURLPurged)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (conn Postgres) ConsumeClick(ctx context.Context, alias string) (int, error) {
+	const op = "internal/repository/postgres/urls.go/ConsumeClick"
+
+	var remaining int
+
+	err := conn.pool.QueryRow(ctx, consumeClickQuery, alias).Scan(&remaining)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, generalerrors.ErrClicksExhausted)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return remaining, nil
+}
+
+func (conn Postgres) PeekClick(ctx context.Context, alias string) (int, error) {
+	const op = "internal/repository/postgres/urls.go/PeekClick"
+
+	var remaining int
+
+	err := conn.pool.QueryRow(ctx, peekClickQuery, alias).Scan(&remaining)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, generalerrors.ErrClicksExhausted)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return remaining, nil
+}
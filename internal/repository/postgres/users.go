@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
 	"github.com/Cwby333/url-shorter/internal/entity/users"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/jackc/pgx/v5"
@@ -62,6 +63,12 @@ func (conn Postgres) CreateUser(ctx context.Context, username string, password s
 		return "", fmt.Errorf("%s: %w", op, generalerrors.ErrUsernameAlreadyExists)
 	}
 
+	err = writeUserEvent(ctx, tx, outbox.EventUserCreated, id)
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
 	return id, nil
 }
 
@@ -175,7 +182,7 @@ func (conn Postgres) ChangeCredentials(ctx context.Context, newUsername string,
 		}
 	}()
 
-	rows, err := conn.pool.Query(ctx, updateUser, newUsername, newPassword, username)
+	rows, err := tx.Query(ctx, updateUser, newUsername, newPassword, username)
 
 	if err != nil {
 		return users.User{}, fmt.Errorf("%s: %w", op, err)
@@ -187,18 +194,22 @@ func (conn Postgres) ChangeCredentials(ctx context.Context, newUsername string,
 		return users.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err = conn.pool.Query(ctx, `UPDATE users SET version = $1, user_blocked = false WHERE username = $2`, user.Version+1, newUsername)
+	_, err = tx.Exec(ctx, `UPDATE users SET version = $1, user_blocked = false WHERE username = $2`, user.Version+1, newUsername)
 
 	if err != nil {
 		return users.User{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows.Close()
+	err = writeUserEvent(ctx, tx, outbox.EventUserUpdated, user.UUID)
+
+	if err != nil {
+		return users.User{}, fmt.Errorf("%s: %w", op, err)
+	}
 
 	return user, nil
 }
 
-func (conn Postgres) BlockUser(ctx context.Context, uuid string) error {
+func (conn Postgres) BlockUser(ctx context.Context, uuid string) (err error) {
 	const op = "internal/repository/postgres/BlockUser"
 
 	tx, err := conn.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: ReadWriteAccessMode})
@@ -226,13 +237,17 @@ func (conn Postgres) BlockUser(ctx context.Context, uuid string) error {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows, err := conn.pool.Query(ctx, blockUser, uuid)
+	_, err = tx.Exec(ctx, blockUser, uuid)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	rows.Close()
+	err = writeUserEvent(ctx, tx, outbox.EventUserBlocked, uuid)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	return nil
 }
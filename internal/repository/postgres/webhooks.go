@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	webhookColumns = `id, url, events, '' AS secret, active, created_at`
+
+	insertWebhookQuery = `INSERT INTO webhooks(owner_uuid, url, events, secret) VALUES($1, $2, $3, $4) RETURNING id, url, events, secret, active, created_at`
+	listWebhooksQuery  = `SELECT ` + webhookColumns + ` FROM webhooks WHERE owner_uuid = $1 ORDER BY id`
+	deleteWebhookQuery = `DELETE FROM webhooks WHERE id = $1 AND owner_uuid = $2`
+	webhookOwnerQuery  = `SELECT 1 FROM webhooks WHERE id = $1 AND owner_uuid = $2`
+
+	enqueueDeliveriesQuery = `INSERT INTO webhook_deliveries(webhook_id, event, payload)
+		SELECT w.id, e.event, e.payload::jsonb FROM unnest($1::text[], $2::text[], $3::text[]) AS e(alias, event, payload)
+		JOIN urls_alias u ON u.alias = e.alias JOIN webhooks w ON w.owner_uuid = u.owner_uuid
+		WHERE w.active AND e.event = ANY(w.events)`
+	enqueueEventQuery = `INSERT INTO webhook_deliveries(webhook_id, event_id, event, payload)
+		SELECT id, $2::uuid, $3, $4 FROM webhooks WHERE owner_uuid = $1::uuid AND active AND $3 = ANY(events)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+	hasSubscribersQuery = `SELECT EXISTS(SELECT 1 FROM webhooks WHERE active AND $1 = ANY(events))`
+	listDeliveriesQuery = `SELECT id, webhook_id, event, payload, status, attempts, next_attempt_at, last_error, response_code, created_at, delivered_at
+		FROM webhook_deliveries WHERE webhook_id = $1 AND ($2 = '' OR status = $2) ORDER BY id DESC LIMIT $3`
+	claimDeliveriesQuery = `UPDATE webhook_deliveries d SET attempts = d.attempts + 1, next_attempt_at = now() + $2::interval FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= now()
+		ORDER BY next_attempt_at LIMIT $1 FOR UPDATE SKIP LOCKED)
+		RETURNING d.id, d.event, d.payload, d.attempts, w.url, w.secret`
+	markDeliveredQuery = `UPDATE webhook_deliveries SET status = 'delivered', delivered_at = now(), response_code = $2, last_error = '' WHERE id = $1`
+	markFailedQuery    = `UPDATE webhook_deliveries SET status = $2, next_attempt_at = now() + $3::interval, response_code = $4, last_error = $5 WHERE id = $1`
+)
+
+func (conn Postgres) CreateWebhook(ctx context.Context, ownerUUID string, hook webhooks.Webhook) (webhooks.Webhook, error) {
+	const op = "internal/repository/postgres/webhooks.go/CreateWebhook"
+
+	rows, err := conn.pool.Query(ctx, insertWebhookQuery, ownerUUID, hook.URL, hook.Events, hook.Secret)
+
+	if err != nil {
+		return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhooks.Webhook])
+
+	if err != nil {
+		return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) ListWebhooks(ctx context.Context, ownerUUID string) ([]webhooks.Webhook, error) {
+	const op = "internal/repository/postgres/webhooks.go/ListWebhooks"
+
+	rows, err := conn.pool.Query(ctx, listWebhooksQuery, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhooks.Webhook])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) DeleteWebhook(ctx context.Context, ownerUUID string, id int64) error {
+	const op = "internal/repository/postgres/webhooks.go/DeleteWebhook"
+
+	tag, err := conn.pool.Exec(ctx, deleteWebhookQuery, id, ownerUUID)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() < 1 {
+		return fmt.Errorf("%s: %w", op, generalerrors.ErrWebhookNotFound)
+	}
+
+	return nil
+}
+
+func (conn Postgres) ListDeliveries(ctx context.Context, ownerUUID string, id int64, status string, limit int) ([]webhooks.Delivery, error) {
+	const op = "internal/repository/postgres/webhooks.go/ListDeliveries"
+
+	var one int
+
+	err := conn.pool.QueryRow(ctx, webhookOwnerQuery, id, ownerUUID).Scan(&one)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrWebhookNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := conn.pool.Query(ctx, listDeliveriesQuery, id, status, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhooks.Delivery])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) EnqueueDeliveries(ctx context.Context, events []webhooks.Event, payloads [][]byte) error {
+	const op = "internal/repository/postgres/webhooks.go/EnqueueDeliveries"
+
+	aliases := make([]string, len(events))
+	types := make([]string, len(events))
+	bodies := make([]string, len(events))
+
+	for i, event := range events {
+		aliases[i] = event.Alias
+		types[i] = event.Type
+		bodies[i] = string(payloads[i])
+	}
+
+	_, err := conn.pool.Exec(ctx, enqueueDeliveriesQuery, aliases, types, bodies)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EnqueueEvent fans an outbox event out to the owner's subscriptions. Redelivery
+// of the same event is a no-op thanks to the (webhook_id, event_id) index.
+func (conn Postgres) EnqueueEvent(ctx context.Context, ownerUUID, eventID, event string, payload []byte) error {
+	const op = "internal/repository/postgres/webhooks.go/EnqueueEvent"
+
+	_, err := conn.pool.Exec(ctx, enqueueEventQuery, ownerUUID, eventID, event, payload)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) HasSubscribers(ctx context.Context, event string) (bool, error) {
+	const op = "internal/repository/postgres/webhooks.go/HasSubscribers"
+
+	var ok bool
+
+	err := conn.pool.QueryRow(ctx, hasSubscribersQuery, event).Scan(&ok)
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ok, nil
+}
+
+func (conn Postgres) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhooks.Task, error) {
+	const op = "internal/repository/postgres/webhooks.go/ClaimDeliveries"
+
+	rows, err := conn.pool.Query(ctx, claimDeliveriesQuery, limit, lease)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhooks.Task])
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (conn Postgres) MarkDelivered(ctx context.Context, id int64, code int) error {
+	const op = "internal/repository/postgres/webhooks.go/MarkDelivered"
+
+	_, err := conn.pool.Exec(ctx, markDeliveredQuery, id, code)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (conn Postgres) MarkFailed(ctx context.Context, id int64, status string, retryIn time.Duration, code int, reason string) error {
+	const op = "internal/repository/postgres/webhooks.go/MarkFailed"
+
+	_, err := conn.pool.Exec(ctx, markFailedQuery, id, status, retryIn, code, reason)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
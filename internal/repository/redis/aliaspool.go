@@ -0,0 +1,64 @@
+package myredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	aliasPoolKey = "alias_pool"
+)
+
+func (r Redis) PopAlias(ctx context.Context) (string, error) {
+	const op = "internal/repository/redis/PopAlias"
+
+	alias, err := r.client.SPop(ctx, aliasPoolKey).Result()
+
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", fmt.Errorf("%s: %w", op, generalerrors.ErrCacheMiss)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return alias, nil
+}
+
+func (r Redis) PushAliases(ctx context.Context, aliases []string) error {
+	const op = "internal/repository/redis/PushAliases"
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	members := make([]any, 0, len(aliases))
+
+	for _, alias := range aliases {
+		members = append(members, alias)
+	}
+
+	err := r.client.SAdd(ctx, aliasPoolKey, members...).Err()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r Redis) PoolSize(ctx context.Context) (int64, error) {
+	const op = "internal/repository/redis/PoolSize"
+
+	size, err := r.client.SCard(ctx, aliasPoolKey).Result()
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return size, nil
+}
@@ -0,0 +1,43 @@
+package myredis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	clicksLeftKeyPrefix = "clicks_left:"
+)
+
+var decrClicksScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+end
+return redis.call("DECR", KEYS[1])
+`)
+
+func (r Redis) DecrClicksLeft(ctx context.Context, alias string, initial int) (int64, error) {
+	const op = "internal/repository/redis/DecrClicksLeft"
+
+	res, err := decrClicksScript.Run(ctx, r.client, []string{clicksLeftKeyPrefix + alias}, initial, r.urlTTL.Milliseconds()).Int64()
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (r Redis) ResetClicksLeft(ctx context.Context, alias string, value int) error {
+	const op = "internal/repository/redis/ResetClicksLeft"
+
+	res := r.client.Set(ctx, clicksLeftKeyPrefix+alias, value, r.urlTTL)
+
+	if res.Err() != nil {
+		return fmt.Errorf("%s: %w", op, res.Err())
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package myredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (r Redis) PublishInvalidation(ctx context.Context, channel string, payload []byte) error {
+	const op = "internal/repository/redis/PublishInvalidation"
+
+	res := r.client.Publish(ctx, channel, payload)
+
+	if res.Err() != nil {
+		return fmt.Errorf("%s: %w", op, res.Err())
+	}
+
+	return nil
+}
+
+func (r Redis) ListenInvalidations(ctx context.Context, channel string, pingInterval time.Duration, onSubscribe func(), onMessage func(payload []byte)) error {
+	const op = "internal/repository/redis/ListenInvalidations"
+
+	pubsub := r.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		pubsub.Close()
+	})
+	defer stop()
+
+	for {
+		msg, err := pubsub.ReceiveTimeout(ctx, pingInterval)
+
+		if err != nil {
+			var netErr net.Error
+
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				err = pubsub.Ping(ctx)
+
+				if err == nil {
+					continue
+				}
+			}
+
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s: %w", op, ctx.Err())
+			}
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				onSubscribe()
+			}
+		case *redis.Message:
+			onMessage([]byte(m.Payload))
+		}
+	}
+}
+
+const flushedKey = "urls:flushed"
+
+// ClearResponsesCache drops the whole responses cache at most once per interval
+// across all instances, so a redis restart that makes each of them resubscribe
+// does not turn into a stampede of flushes and cold reads. A forced flush skips
+// that limit, for lost invalidations whose aliases are unknown.
+func (r Redis) ClearResponsesCache(ctx context.Context, interval time.Duration, force bool) (bool, error) {
+	const op = "internal/repository/redis/ClearResponsesCache"
+
+	if force {
+		err := r.client.Set(ctx, flushedKey, 1, interval).Err()
+
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+	} else {
+		ok, err := r.client.SetNX(ctx, flushedKey, 1, interval).Result()
+
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	err := r.client.Del(ctx, "urls").Err()
+
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return true, nil
+}
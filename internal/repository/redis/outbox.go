@@ -0,0 +1,34 @@
+package myredis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/redis/go-redis/v9"
+)
+
+func (r Redis) PublishEvent(ctx context.Context, stream string, maxLen int64, event outbox.Event) error {
+	const op = "internal/repository/redis/PublishEvent"
+
+	res := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]any{
+			"id":           event.EventID,
+			"aggregate":    event.Aggregate,
+			"aggregate_id": event.AggregateID,
+			"event":        event.Type,
+			"payload":      string(event.Payload),
+			"created_at":   event.CreatedAt.Format(time.RFC3339Nano),
+		},
+	})
+
+	if res.Err() != nil {
+		return fmt.Errorf("%s: %w", op, res.Err())
+	}
+
+	return nil
+}
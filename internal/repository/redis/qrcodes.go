@@ -0,0 +1,42 @@
+package myredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	qrCodePrefix = "qr:"
+)
+
+func (r Redis) GetQRCode(ctx context.Context, key string) ([]byte, error) {
+	const op = "internal/repository/redis/GetQRCode"
+
+	data, err := r.client.Get(ctx, qrCodePrefix+key).Bytes()
+
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrCacheMiss)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return data, nil
+}
+
+func (r Redis) SaveQRCode(ctx context.Context, key string, data []byte) error {
+	const op = "internal/repository/redis/SaveQRCode"
+
+	err := r.client.Set(ctx, qrCodePrefix+key, data, r.urlTTL).Err()
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -2,17 +2,42 @@ package myredis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/redis/go-redis/v9"
 )
 
-func (r Redis) SaveResponseInCache(ctx context.Context, alias string, response string) error {
+func (r Redis) SaveResponseInCache(ctx context.Context, alias string, response urls.URL) error {
 	const op = "internal/repository/redis/SaveResponse"
 
-	res := r.client.HSet(ctx, "urls", alias, response)
+	if response.Protected {
+		response.URL = ""
+		response.Destinations = nil
+		response.Rules = nil
+	}
+
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ttl := r.urlTTL
+
+	if response.ExpiresAt != nil {
+		ttl = min(ttl, max(time.Until(*response.ExpiresAt), time.Second))
+	}
+
+	if response.ActiveFrom != nil && time.Until(*response.ActiveFrom) > 0 {
+		ttl = min(ttl, max(time.Until(*response.ActiveFrom), time.Second))
+	}
+
+	res := r.client.HSet(ctx, "urls", alias, data)
 
 	if res.Err() != nil {
 		return fmt.Errorf("%s; %w", op, res.Err())
@@ -20,13 +45,13 @@ func (r Redis) SaveResponseInCache(ctx context.Context, alias string, response s
 
 	select {
 	case <-ctx.Done():
-		res2 := r.client.HExpire(context.Background(), "urls", r.urlTTL, alias)
+		res2 := r.client.HExpire(context.Background(), "urls", ttl, alias)
 
 		if res2.Err() != nil {
 			return fmt.Errorf("%s: %w", op, res2.Err())
 		}
 	default:
-		res2 := r.client.HExpire(ctx, "urls", r.urlTTL, alias)
+		res2 := r.client.HExpire(ctx, "urls", ttl, alias)
 
 		if res2.Err() != nil {
 			return fmt.Errorf("%s: %w", op, res2.Err())
@@ -36,23 +61,30 @@ func (r Redis) SaveResponseInCache(ctx context.Context, alias string, response s
 	return nil
 }
 
-func (r Redis) GetResponseFromCache(ctx context.Context, alias string) (string, error) {
+func (r Redis) GetResponseFromCache(ctx context.Context, alias string) (urls.URL, error) {
 	const op = "internal/repository/redis/GetResponse"
 
 	res := r.client.HGet(ctx, "urls", alias)
 
 	if res.Err() != nil {
 		if errors.Is(res.Err(), redis.Nil) {
-			return "", fmt.Errorf("%s: %w", op, generalerrors.ErrCacheMiss)
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrCacheMiss)
 		}
 
-		return "", fmt.Errorf("%s: %w", op, res.Err())
+		return urls.URL{}, fmt.Errorf("%s: %w", op, res.Err())
 	}
 
-	response, err := res.Result()
+	data, err := res.Bytes()
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	response := urls.URL{}
+	err = json.Unmarshal(data, &response)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return response, nil
@@ -61,10 +93,15 @@ func (r Redis) GetResponseFromCache(ctx context.Context, alias string) (string,
 func (r Redis) RemoveResponseFromCache(ctx context.Context, alias string) error {
 	const op = "internal/repository/redis/RemoveFromCache"
 
-	res := r.client.HDel(ctx, "urls", alias)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, "urls", alias)
+		pipe.Del(ctx, clicksLeftKeyPrefix+alias)
 
-	if res.Err() != nil {
-		return fmt.Errorf("%s: %w", op, res.Err())
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
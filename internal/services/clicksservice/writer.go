@@ -0,0 +1,190 @@
+package clicksservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+	"github.com/Cwby333/url-shorter/internal/useragent"
+)
+
+const (
+	DefaultTimeoutForFlush = time.Duration(time.Second * 2)
+
+	ipv4KeepBits = 24
+	ipv6KeepBits = 48
+)
+
+type ClicksRepository interface {
+	SaveClicks(ctx context.Context, batch []clicks.Click) error
+}
+
+type GeoLocator interface {
+	Country(addr string) string
+}
+
+type Writer struct {
+	repo   ClicksRepository
+	geo    GeoLocator
+	logger logger.Logger
+
+	events        chan clicks.Click
+	batchSize     int
+	flushInterval time.Duration
+	dropped       *atomic.Int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(repo ClicksRepository, geo GeoLocator, logger logger.Logger, cfg config.Clicks) (Writer, error) {
+	const op = "internal/services/clicksservice/New"
+
+	if repo == (ClicksRepository)(nil) {
+		logger.Error("nil pointer in interface ClicksRepository")
+
+		return Writer{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if geo == (GeoLocator)(nil) {
+		logger.Error("nil pointer in interface GeoLocator")
+
+		return Writer{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	return Writer{
+		repo:          repo,
+		geo:           geo,
+		logger:        logger,
+		events:        make(chan clicks.Click, cfg.BufferSize),
+		batchSize:     cfg.BatchSize,
+		flushInterval: cfg.FlushInterval,
+		dropped:       &atomic.Int64{},
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}, nil
+}
+
+func (w Writer) Track(click clicks.Click) {
+	click.Country = w.geo.Country(click.IP)
+	click.Browser = useragent.Browser(click.UserAgent)
+	click.IP = AnonymizeIP(click.IP)
+
+	select {
+	case <-w.stop:
+		w.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case w.events <- click:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+func (w Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+func (w Writer) Start() {
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.flushInterval)
+		defer ticker.Stop()
+
+		batch := make([]clicks.Click, 0, w.batchSize)
+
+		for {
+			select {
+			case click := <-w.events:
+				batch = append(batch, click)
+
+				if len(batch) >= w.batchSize {
+					batch = w.flush(batch)
+				}
+			case <-ticker.C:
+				batch = w.flush(batch)
+			case <-w.stop:
+				for {
+					select {
+					case click := <-w.events:
+						batch = append(batch, click)
+
+						if len(batch) >= w.batchSize {
+							batch = w.flush(batch)
+						}
+					default:
+						w.flush(batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (w Writer) flush(batch []clicks.Click) []clicks.Click {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeoutForFlush)
+	defer cancel()
+
+	err := w.repo.SaveClicks(ctx, batch)
+
+	if err != nil {
+		w.logger.Error("save clicks", slog.String("error", err.Error()), slog.Int("lost", len(batch)))
+	}
+
+	if dropped := w.dropped.Swap(0); dropped > 0 {
+		w.logger.Warn("clicks dropped, buffer is full", slog.Int64("dropped", dropped))
+	}
+
+	return batch[:0]
+}
+
+func (w Writer) Close() chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		close(w.stop)
+		<-w.done
+		ch <- nil
+	}()
+
+	return ch
+}
+
+func (w Writer) ContextInfo() string {
+	return "clicks writer"
+}
+
+func AnonymizeIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+
+	if err != nil {
+		host = addr
+	}
+
+	ip := net.ParseIP(host)
+
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(ipv4KeepBits, 32)).String()
+	}
+
+	return ip.Mask(net.CIDRMask(ipv6KeepBits, 128)).String()
+}
@@ -0,0 +1,51 @@
+package invalidationservice
+
+import "time"
+
+// maxPendingGap bounds how many skipped sequence numbers are remembered per
+// instance; a larger jump is reported as missed right away.
+const maxPendingGap = 1024
+
+// sequence tracks the messages seen from one instance. Concurrent Invalidate
+// calls on that instance may publish out of order, so a skipped number is only
+// reported as missed once it has stayed absent for the reorder window.
+type sequence struct {
+	highest uint64
+	pending map[uint64]time.Time
+}
+
+func newSequence(first uint64) *sequence {
+	return &sequence{
+		highest: first - 1,
+		pending: make(map[uint64]time.Time),
+	}
+}
+
+// observe records seq and returns how many earlier messages are now considered lost.
+func (s *sequence) observe(seq uint64, now time.Time, window time.Duration) int {
+	missed := 0
+
+	switch {
+	case seq <= s.highest:
+		delete(s.pending, seq)
+	case seq-s.highest-1 > maxPendingGap:
+		missed += int(seq-s.highest-1) + len(s.pending)
+		clear(s.pending)
+		s.highest = seq
+	default:
+		for n := s.highest + 1; n < seq; n++ {
+			s.pending[n] = now
+		}
+
+		s.highest = seq
+	}
+
+	for n, since := range s.pending {
+		if now.Sub(since) >= window {
+			delete(s.pending, n)
+			missed++
+		}
+	}
+
+	return missed
+}
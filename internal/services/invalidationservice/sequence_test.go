@@ -0,0 +1,72 @@
+package invalidationservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSequenceObserve(t *testing.T) {
+	const window = 2 * time.Second
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type step struct {
+		seq    uint64
+		after  time.Duration
+		missed int
+	}
+
+	tests := []struct {
+		name  string
+		first uint64
+		steps []step
+	}{
+		{
+			name:  "in order",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 2}, {seq: 3}},
+		},
+		{
+			name:  "first message may be any number",
+			first: 41,
+			steps: []step{{seq: 41}, {seq: 42}},
+		},
+		{
+			name:  "reordered within the window",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 3}, {seq: 2, after: time.Second}, {seq: 4, after: 3 * time.Second}},
+		},
+		{
+			name:  "gap reported after the window",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 4}, {seq: 5, after: time.Second}, {seq: 6, after: window, missed: 2}},
+		},
+		{
+			name:  "late message after the gap was reported",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 3}, {seq: 4, after: window, missed: 1}, {seq: 2, after: window + time.Second}},
+		},
+		{
+			name:  "duplicate",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 2}, {seq: 2}},
+		},
+		{
+			name:  "huge jump is reported at once",
+			first: 1,
+			steps: []step{{seq: 1}, {seq: 3}, {seq: maxPendingGap + 10, missed: maxPendingGap + 7}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSequence(tt.first)
+
+			for i, step := range tt.steps {
+				if got := s.observe(step.seq, start.Add(step.after), window); got != step.missed {
+					t.Errorf("step %d: observe(%d) = %d, want %d", i, step.seq, got, step.missed)
+				}
+			}
+		})
+	}
+}
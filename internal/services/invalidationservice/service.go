@@ -0,0 +1,230 @@
+package invalidationservice
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+	"github.com/google/uuid"
+)
+
+var metrics = expvar.NewMap("cache_invalidation")
+
+type Broker interface {
+	PublishInvalidation(ctx context.Context, channel string, payload []byte) error
+	ListenInvalidations(ctx context.Context, channel string, pingInterval time.Duration, onSubscribe func(), onMessage func(payload []byte)) error
+}
+
+type Cache interface {
+	RemoveResponseFromCache(ctx context.Context, alias string) error
+	ClearResponsesCache(ctx context.Context, interval time.Duration, force bool) (bool, error)
+}
+
+type message struct {
+	Instance string   `json:"instance"`
+	Seq      uint64   `json:"seq"`
+	Aliases  []string `json:"aliases"`
+}
+
+type Service struct {
+	broker Broker
+	cache  Cache
+	logger logger.Logger
+	cfg    config.Invalidation
+
+	instance string
+	seq      *atomic.Uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(broker Broker, cache Cache, logger logger.Logger, cfg config.Invalidation) (Service, error) {
+	const op = "internal/services/invalidationservice/New"
+
+	if broker == (Broker)(nil) {
+		logger.Error("nil pointer in interface Broker")
+
+		return Service{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if cache == (Cache)(nil) {
+		logger.Error("nil pointer in interface Cache")
+
+		return Service{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return Service{
+		broker:   broker,
+		cache:    cache,
+		logger:   logger,
+		cfg:      cfg,
+		instance: uuid.NewString(),
+		seq:      &atomic.Uint64{},
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (s Service) Invalidate(ctx context.Context, aliases ...string) {
+	if len(aliases) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(message{
+		Instance: s.instance,
+		Seq:      s.seq.Add(1),
+		Aliases:  aliases,
+	})
+
+	if err != nil {
+		s.logger.Error("json marshal", slog.String("error", err.Error()))
+		return
+	}
+
+	err = s.broker.PublishInvalidation(ctx, s.cfg.Channel, payload)
+
+	if err != nil {
+		metrics.Add("publish_errors", 1)
+
+		s.logger.Error("publish cache invalidation", slog.String("error", err.Error()), slog.Any("aliases", aliases))
+		return
+	}
+
+	metrics.Add("published", 1)
+}
+
+func (s Service) Start() {
+	go func() {
+		defer close(s.done)
+
+		var (
+			subscribed bool
+			sequences  = make(map[string]*sequence)
+		)
+
+		backoff := s.cfg.BackoffMin
+
+		for {
+			err := s.broker.ListenInvalidations(s.ctx, s.cfg.Channel, s.cfg.PingInterval, func() {
+				backoff = s.cfg.BackoffMin
+
+				if subscribed {
+					metrics.Add("reconnects", 1)
+
+					s.logger.Info("cache invalidation resubscribed, flushing cache")
+
+					s.flush(false)
+				}
+
+				subscribed = true
+			}, func(payload []byte) {
+				s.apply(payload, sequences)
+			})
+
+			if s.ctx.Err() != nil {
+				return
+			}
+
+			metrics.Add("listen_errors", 1)
+
+			s.logger.Warn("cache invalidation subscription lost", slog.String("error", err.Error()), slog.Duration("retry_in", backoff))
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(2*backoff, s.cfg.BackoffMax)
+		}
+	}()
+}
+
+func (s Service) apply(payload []byte, sequences map[string]*sequence) {
+	msg := message{}
+	err := json.Unmarshal(payload, &msg)
+
+	if err != nil {
+		metrics.Add("malformed", 1)
+
+		s.logger.Error("json unmarshal", slog.String("error", err.Error()))
+		return
+	}
+
+	if msg.Instance == s.instance {
+		return
+	}
+
+	metrics.Add("received", 1)
+
+	seq, ok := sequences[msg.Instance]
+
+	if !ok {
+		seq = newSequence(msg.Seq)
+		sequences[msg.Instance] = seq
+	}
+
+	if missed := seq.observe(msg.Seq, time.Now(), s.cfg.ReorderWindow); missed > 0 {
+		metrics.Add("missed", int64(missed))
+
+		s.logger.Warn("cache invalidation messages missed", slog.String("instance", msg.Instance), slog.Int("missed", missed))
+
+		s.flush(true)
+	}
+
+	for _, alias := range msg.Aliases {
+		err := s.cache.RemoveResponseFromCache(s.ctx, alias)
+
+		if err != nil {
+			s.logger.Error("cache", slog.String("error", err.Error()), slog.String("alias", alias))
+			continue
+		}
+
+		metrics.Add("applied", 1)
+	}
+}
+
+// flush clears the shared cache. Resubscribes are rate limited by FlushEvery,
+// lost messages force the flush because their aliases would stay stale otherwise.
+func (s Service) flush(force bool) {
+	flushed, err := s.cache.ClearResponsesCache(s.ctx, s.cfg.FlushEvery, force)
+
+	if err != nil {
+		s.logger.Error("cache", slog.String("error", err.Error()))
+		return
+	}
+
+	if !flushed {
+		metrics.Add("flushes_skipped", 1)
+		return
+	}
+
+	metrics.Add("flushes", 1)
+}
+
+func (s Service) Close() chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		s.cancel()
+		<-s.done
+		ch <- nil
+	}()
+
+	return ch
+}
+
+func (s Service) ContextInfo() string {
+	return "cache invalidation subscriber"
+}
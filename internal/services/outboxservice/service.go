@@ -0,0 +1,151 @@
+package outboxservice
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+type Repository interface {
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]outbox.Event, error)
+	MarkOutboxPublished(ctx context.Context, ids []int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, retryIn time.Duration, dead bool, reason string) error
+	PurgeOutbox(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event outbox.Event) error
+}
+
+type Relay struct {
+	repo   Repository
+	sinks  []Sink
+	logger logger.Logger
+	cfg    config.Outbox
+}
+
+func New(repo Repository, sinks []Sink, logger logger.Logger, cfg config.Outbox) (Relay, error) {
+	const op = "internal/services/outboxservice/New"
+
+	if repo == (Repository)(nil) {
+		logger.Error("nil pointer in interface Repository")
+
+		return Relay{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	for _, sink := range sinks {
+		if sink == (Sink)(nil) {
+			logger.Error("nil pointer in interface Sink")
+
+			return Relay{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+		}
+	}
+
+	return Relay{
+		repo:   repo,
+		sinks:  sinks,
+		logger: logger,
+		cfg:    cfg,
+	}, nil
+}
+
+func (relay Relay) Relay(ctx context.Context) (int64, error) {
+	const op = "internal/services/outboxservice/Relay"
+
+	var total int64
+
+	for {
+		events, err := relay.repo.ClaimOutbox(ctx, relay.cfg.BatchSize, relay.cfg.Lease)
+
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+
+		published := relay.publish(ctx, events)
+		total += int64(len(published))
+
+		err = relay.repo.MarkOutboxPublished(ctx, published)
+
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(events) < relay.cfg.BatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// publish hands every event to all sinks. A failed event is rescheduled with
+// backoff, or dead-lettered after MaxAttempts, and the batch moves on; sinks
+// must therefore tolerate redelivery of events they have already accepted.
+func (relay Relay) publish(ctx context.Context, events []outbox.Event) []int64 {
+	published := make([]int64, 0, len(events))
+
+	for _, event := range events {
+		err := relay.publishEvent(ctx, event)
+
+		if err == nil {
+			published = append(published, event.ID)
+
+			continue
+		}
+
+		dead := event.Attempts >= relay.cfg.MaxAttempts
+
+		if dead {
+			relay.logger.Error("outbox event dead-lettered", slog.String("event_id", event.EventID), slog.Int("attempt", event.Attempts), slog.String("error", err.Error()))
+		} else {
+			relay.logger.Info("publish outbox event", slog.String("event_id", event.EventID), slog.Int("attempt", event.Attempts), slog.String("error", err.Error()))
+		}
+
+		e := relay.repo.MarkOutboxFailed(ctx, event.ID, relay.backoff(event.Attempts), dead, err.Error())
+
+		if e != nil {
+			relay.logger.Error("mark outbox event failed", slog.String("event_id", event.EventID), slog.String("error", e.Error()))
+		}
+	}
+
+	return published
+}
+
+func (relay Relay) publishEvent(ctx context.Context, event outbox.Event) error {
+	for _, sink := range relay.sinks {
+		err := sink.Publish(ctx, event)
+
+		if err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (relay Relay) backoff(attempt int) time.Duration {
+	delay := relay.cfg.BackoffBase
+
+	for i := 1; i < attempt && delay < relay.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, relay.cfg.BackoffMax)
+}
+
+func (relay Relay) Purge(ctx context.Context) (int64, error) {
+	const op = "internal/services/outboxservice/Purge"
+
+	n, err := relay.repo.PurgeOutbox(ctx, relay.cfg.Retention)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
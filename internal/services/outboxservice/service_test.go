@@ -0,0 +1,207 @@
+package outboxservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+type failure struct {
+	id      int64
+	retryIn time.Duration
+	dead    bool
+	reason  string
+}
+
+type fakeRepo struct {
+	batches   [][]outbox.Event
+	published []int64
+	failures  []failure
+	retention time.Duration
+	purgeErr  error
+}
+
+func (r *fakeRepo) ClaimOutbox(_ context.Context, _ int, _ time.Duration) ([]outbox.Event, error) {
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+
+	return batch, nil
+}
+
+func (r *fakeRepo) MarkOutboxPublished(_ context.Context, ids []int64) error {
+	r.published = append(r.published, ids...)
+
+	return nil
+}
+
+func (r *fakeRepo) MarkOutboxFailed(_ context.Context, id int64, retryIn time.Duration, dead bool, reason string) error {
+	r.failures = append(r.failures, failure{id: id, retryIn: retryIn, dead: dead, reason: reason})
+
+	return nil
+}
+
+func (r *fakeRepo) PurgeOutbox(_ context.Context, retention time.Duration) (int64, error) {
+	r.retention = retention
+
+	if r.purgeErr != nil {
+		return 0, r.purgeErr
+	}
+
+	return 7, nil
+}
+
+type fakeSink struct {
+	name     string
+	rejected map[string]bool
+	received []string
+}
+
+func (s *fakeSink) Name() string {
+	return s.name
+}
+
+func (s *fakeSink) Publish(_ context.Context, event outbox.Event) error {
+	s.received = append(s.received, event.EventID)
+
+	if s.rejected[event.EventID] {
+		return errors.New("rejected")
+	}
+
+	return nil
+}
+
+func testConfig() config.Outbox {
+	return config.Outbox{
+		BatchSize:   2,
+		Lease:       30 * time.Second,
+		MaxAttempts: 3,
+		BackoffBase: 5 * time.Second,
+		BackoffMax:  time.Minute,
+		Retention:   24 * time.Hour,
+	}
+}
+
+func newTestRelay(t *testing.T, repo Repository, sinks ...Sink) Relay {
+	t.Helper()
+
+	relay, err := New(repo, sinks, logger.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}, testConfig())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return relay
+}
+
+func TestBackoff(t *testing.T) {
+	relay := Relay{cfg: testConfig()}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 5 * time.Second},
+		{attempt: 1, want: 5 * time.Second},
+		{attempt: 2, want: 10 * time.Second},
+		{attempt: 4, want: 40 * time.Second},
+		{attempt: 5, want: time.Minute},
+		{attempt: 100, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := relay.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRelay(t *testing.T) {
+	repo := &fakeRepo{batches: [][]outbox.Event{
+		{{ID: 1, EventID: "a", Attempts: 1}, {ID: 2, EventID: "b", Attempts: 2}},
+		{{ID: 3, EventID: "c", Attempts: 3}},
+	}}
+	first := &fakeSink{name: "first", rejected: map[string]bool{"b": true}}
+	second := &fakeSink{name: "second", rejected: map[string]bool{"c": true}}
+
+	total, err := newTestRelay(t, repo, first, second).Relay(context.Background())
+	if err != nil {
+		t.Fatalf("Relay() error = %v", err)
+	}
+
+	if total != 1 || !slices.Equal(repo.published, []int64{1}) {
+		t.Errorf("Relay() = %d, published %v, want 1, [1]", total, repo.published)
+	}
+
+	if want := []string{"a", "b", "c"}; !slices.Equal(first.received, want) {
+		t.Errorf("first sink received %v, want %v", first.received, want)
+	}
+
+	if want := []string{"a", "c"}; !slices.Equal(second.received, want) {
+		t.Errorf("second sink received %v, want %v", second.received, want)
+	}
+
+	want := []failure{
+		{id: 2, retryIn: 10 * time.Second, dead: false, reason: "sink first: rejected"},
+		{id: 3, retryIn: 20 * time.Second, dead: true, reason: "sink second: rejected"},
+	}
+
+	if !slices.Equal(repo.failures, want) {
+		t.Errorf("failures = %+v, want %+v", repo.failures, want)
+	}
+}
+
+func TestRelayDeadLetter(t *testing.T) {
+	tests := []struct {
+		attempts int
+		dead     bool
+	}{
+		{attempts: 1, dead: false},
+		{attempts: 2, dead: false},
+		{attempts: 3, dead: true},
+		{attempts: 4, dead: true},
+	}
+
+	for _, tt := range tests {
+		repo := &fakeRepo{batches: [][]outbox.Event{{{ID: 1, EventID: "a", Attempts: tt.attempts}}}}
+		sink := &fakeSink{name: "sink", rejected: map[string]bool{"a": true}}
+
+		_, err := newTestRelay(t, repo, sink).Relay(context.Background())
+		if err != nil {
+			t.Fatalf("Relay() error = %v", err)
+		}
+
+		if len(repo.failures) != 1 || repo.failures[0].dead != tt.dead {
+			t.Errorf("attempt %d: failures = %+v, want dead = %v", tt.attempts, repo.failures, tt.dead)
+		}
+	}
+}
+
+func TestPurge(t *testing.T) {
+	repo := &fakeRepo{}
+
+	n, err := newTestRelay(t, repo).Purge(context.Background())
+	if err != nil || n != 7 {
+		t.Errorf("Purge() = %d, %v, want 7, nil", n, err)
+	}
+
+	if repo.retention != testConfig().Retention {
+		t.Errorf("Purge() retention = %v, want %v", repo.retention, testConfig().Retention)
+	}
+
+	repo.purgeErr = errors.New("db down")
+
+	if _, err := newTestRelay(t, repo).Purge(context.Background()); !errors.Is(err, repo.purgeErr) {
+		t.Errorf("Purge() error = %v, want %v", err, repo.purgeErr)
+	}
+}
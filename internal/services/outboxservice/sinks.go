@@ -0,0 +1,155 @@
+package outboxservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/services/webhookservice"
+)
+
+const (
+	SinkRedis   = "redis"
+	SinkWebhook = "webhook"
+	SinkStdout  = "stdout"
+
+	maxResponseBody = 4096
+)
+
+type StreamPublisher interface {
+	PublishEvent(ctx context.Context, stream string, maxLen int64, event outbox.Event) error
+}
+
+func NewSinks(cfg config.Outbox, streams StreamPublisher, control func(network, address string, c syscall.RawConn) error) ([]Sink, error) {
+	const op = "internal/services/outboxservice/NewSinks"
+
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+
+	for _, name := range cfg.Sinks {
+		switch name {
+		case SinkRedis:
+			if streams == (StreamPublisher)(nil) {
+				return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+			}
+
+			sinks = append(sinks, streamSink{publisher: streams, stream: cfg.Stream, maxLen: cfg.StreamMaxLen})
+		case SinkWebhook:
+			if cfg.WebhookURL == "" {
+				return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrOutboxWebhookURL)
+			}
+
+			sinks = append(sinks, webhookSink{
+				client: webhookservice.NewClient(cfg.Timeout, control),
+				url:    cfg.WebhookURL,
+				secret: cfg.WebhookSecret,
+			})
+		case SinkStdout:
+			sinks = append(sinks, &writerSink{w: os.Stdout})
+		default:
+			return nil, fmt.Errorf("%s: %s: %w", op, name, generalerrors.ErrUnknownOutboxSink)
+		}
+	}
+
+	return sinks, nil
+}
+
+type streamSink struct {
+	publisher StreamPublisher
+	stream    string
+	maxLen    int64
+}
+
+func (s streamSink) Name() string {
+	return SinkRedis
+}
+
+func (s streamSink) Publish(ctx context.Context, event outbox.Event) error {
+	return s.publisher.PublishEvent(ctx, s.stream, s.maxLen, event)
+}
+
+type webhookSink struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func (s webhookSink) Name() string {
+	return SinkWebhook
+}
+
+func (s webhookSink) Publish(ctx context.Context, event outbox.Event) error {
+	body, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "url-shorter-outbox")
+	req.Header.Set("Idempotency-Key", event.EventID)
+	req.Header.Set(webhookservice.HeaderEvent, event.Type)
+	req.Header.Set(webhookservice.HeaderDelivery, event.EventID)
+	req.Header.Set(webhookservice.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+
+	if s.secret != "" {
+		req.Header.Set(webhookservice.HeaderSignature, webhookservice.Sign(s.secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *writerSink) Name() string {
+	return SinkStdout
+}
+
+func (s *writerSink) Publish(ctx context.Context, event outbox.Event) error {
+	data, err := json.Marshal(event)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(data, '\n'))
+
+	return err
+}
@@ -0,0 +1,111 @@
+package qrservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+type Options struct {
+	Format string
+	Level  string
+	Size   int
+}
+
+func (o Options) WithDefaults() Options {
+	if o.Format == "" {
+		o.Format = FormatPNG
+	}
+	if o.Size == 0 {
+		o.Size = DefaultSize
+	}
+
+	return o
+}
+
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
+
+func (s QRService) Generate(ctx context.Context, alias, ownerUUID, content string, opts Options) ([]byte, error) {
+	const op = "internal/services/qrservice/Generate"
+
+	opts = opts.WithDefaults()
+
+	level, err := qrcode.ParseLevel(opts.Level)
+
+	if err != nil || opts.Size < MinSize || opts.Size > MaxSize || opts.Format != FormatPNG && opts.Format != FormatSVG {
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidQROptions)
+	}
+
+	url, err := s.repo.GetURL(ctx, alias)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if url.OwnerUUID == "" || url.OwnerUUID != ownerUUID {
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
+
+	key := opts.Format + ":" + level.String() + ":" + strconv.Itoa(opts.Size) + ":" + content
+
+	data, err := s.cache.GetQRCode(ctx, key)
+
+	if err == nil {
+		return data, nil
+	}
+
+	if !errors.Is(err, generalerrors.ErrCacheMiss) {
+		s.logger.Error("qr cache", slog.String("error", err.Error()))
+	}
+
+	code, err := qrcode.Encode([]byte(content), level)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if opts.Format == FormatSVG {
+		err = code.SVG(buf, opts.Size)
+	} else {
+		err = code.PNG(buf, opts.Size)
+	}
+
+	if err != nil {
+		if errors.Is(err, qrcode.ErrSizeTooSmall) {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidQROptions)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.cache.SaveQRCode(ctx, key, buf.Bytes())
+
+	if err != nil {
+		s.logger.Error("qr cache", slog.String("error", err.Error()))
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package qrservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+type QRRepository interface {
+	GetURL(ctx context.Context, alias string) (urls.URL, error)
+}
+
+type QRCache interface {
+	GetQRCode(ctx context.Context, key string) ([]byte, error)
+	SaveQRCode(ctx context.Context, key string, data []byte) error
+}
+
+type QRService struct {
+	repo   QRRepository
+	cache  QRCache
+	logger logger.Logger
+}
+
+func New(repo QRRepository, cache QRCache, logger logger.Logger) (QRService, error) {
+	const op = "internal/services/qrservice/New"
+
+	if repo == (QRRepository)(nil) {
+		logger.Error("nil pointer in interface QRRepository")
+
+		return QRService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if cache == (QRCache)(nil) {
+		logger.Error("nil pointer in interface QRCache")
+
+		return QRService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	return QRService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package statsservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+type StatsRepository interface {
+	GetURL(ctx context.Context, alias string) (urls.URL, error)
+	ClicksSeries(ctx context.Context, alias, interval string, from, to time.Time) ([]clicks.Bucket, error)
+	TopClicksDimension(ctx context.Context, alias, dimension string, from, to time.Time, limit int) ([]clicks.Counter, error)
+}
+
+type StatsService struct {
+	repo   StatsRepository
+	logger logger.Logger
+}
+
+func New(repo StatsRepository, logger logger.Logger) (StatsService, error) {
+	const op = "internal/services/statsservice/New"
+
+	if repo == (StatsRepository)(nil) {
+		logger.Error("nil pointer in interface StatsRepository")
+
+		return StatsService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	return StatsService{
+		repo:   repo,
+		logger: logger,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package statsservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+const (
+	defaultRange = time.Duration(time.Hour * 24 * 7)
+	maxHourRange = time.Duration(time.Hour * 24 * 31)
+	maxDayRange  = time.Duration(time.Hour * 24 * 366)
+
+	topLimit = 10
+)
+
+func (s StatsService) GetStats(ctx context.Context, alias, ownerUUID string, from, to time.Time, interval string) (clicks.Stats, error) {
+	const op = "internal/services/statsservice/GetStats"
+
+	if interval == "" {
+		interval = clicks.IntervalDay
+	}
+
+	step := time.Duration(time.Hour * 24)
+	maxRange := maxDayRange
+
+	switch interval {
+	case clicks.IntervalDay:
+	case clicks.IntervalHour:
+		step = time.Hour
+		maxRange = maxHourRange
+	default:
+		return clicks.Stats{}, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidStatsInterval)
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultRange)
+	}
+
+	from, to = from.UTC().Truncate(step), to.UTC()
+
+	if !from.Before(to) || to.Sub(from) > maxRange {
+		return clicks.Stats{}, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidStatsRange)
+	}
+
+	url, err := s.repo.GetURL(ctx, alias)
+
+	if err != nil {
+		return clicks.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if url.OwnerUUID == "" || url.OwnerUUID != ownerUUID {
+		return clicks.Stats{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
+
+	series, err := s.repo.ClicksSeries(ctx, alias, interval, from, to)
+
+	if err != nil {
+		return clicks.Stats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats := clicks.Stats{
+		Alias:    alias,
+		From:     from,
+		To:       to,
+		Interval: interval,
+		Clicks:   fillBuckets(series, from, to, step),
+	}
+
+	for _, bucket := range series {
+		stats.Total += bucket.Count
+	}
+
+	top := map[string]*[]clicks.Counter{
+		clicks.DimensionReferrer: &stats.Referrers,
+		clicks.DimensionBrowser:  &stats.Browsers,
+		clicks.DimensionCountry:  &stats.Countries,
+		clicks.DimensionVariant:  &stats.Variants,
+	}
+
+	for dimension, dst := range top {
+		counters, err := s.repo.TopClicksDimension(ctx, alias, dimension, from, to, topLimit)
+
+		if err != nil {
+			return clicks.Stats{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if counters == nil {
+			counters = []clicks.Counter{}
+		}
+
+		*dst = counters
+	}
+
+	return stats, nil
+}
+
+func fillBuckets(series []clicks.Bucket, from, to time.Time, step time.Duration) []clicks.Bucket {
+	counts := make(map[time.Time]int64, len(series))
+
+	for _, bucket := range series {
+		counts[bucket.Time.UTC()] = bucket.Count
+	}
+
+	out := make([]clicks.Bucket, 0, to.Sub(from)/step+1)
+
+	for at := from; at.Before(to); at = at.Add(step) {
+		out = append(out, clicks.Bucket{
+			Time:  at,
+			Count: counts[at],
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,23 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+)
+
+func (service URLService) SetActivation(ctx context.Context, alias, ownerUUID string, activeFrom *time.Time, comingSoon bool) (urls.URL, error) {
+	const op = "internal/services/urlservice/activation.go/SetActivation"
+
+	url, err := service.repo.SetActivation(ctx, alias, ownerUUID, activeFrom, comingSoon)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return url, nil
+}
@@ -0,0 +1,362 @@
+package urlsservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+const (
+	GeneratorRandom   = "random"
+	GeneratorSequence = "sequence"
+	GeneratorPool     = "pool"
+
+	maxGenerateAttempts     = 10
+	maxFilteredAttempts     = 100
+	attemptsPerExtraRune    = 3
+	defaultTimeoutForRefill = time.Duration(time.Second * 5)
+)
+
+// AliasGenerator returns a fresh alias for the given attempt. Attempts after a
+// collision may produce longer aliases so that a crowded keyspace cannot make
+// creation fail.
+type AliasGenerator interface {
+	Next(ctx context.Context, attempt int) (string, error)
+	Close() chan error
+	ContextInfo() string
+}
+
+type AliasSequence interface {
+	NextAliasSeq(ctx context.Context) (int64, error)
+}
+
+type AliasPool interface {
+	PopAlias(ctx context.Context) (string, error)
+	PushAliases(ctx context.Context, aliases []string) error
+	PoolSize(ctx context.Context) (int64, error)
+}
+
+type AliasLookup interface {
+	FreeAliases(ctx context.Context, aliases []string) ([]string, error)
+}
+
+type AliasFilter interface {
+	Allowed(alias string) bool
+}
+
+// NewAliasGenerator builds the configured generator. When filter is set, aliases it
+// rejects are skipped as if they had collided.
+func NewAliasGenerator(cfg config.Aliases, seq AliasSequence, lookup AliasLookup, pool AliasPool, filter AliasFilter, logger logger.Logger) (AliasGenerator, error) {
+	const op = "internal/services/urlservice/NewAliasGenerator"
+
+	generator, err := newAliasGenerator(cfg, seq, lookup, pool, logger)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if filter == (AliasFilter)(nil) {
+		return generator, nil
+	}
+
+	return FilteredGenerator{AliasGenerator: generator, filter: filter}, nil
+}
+
+func newAliasGenerator(cfg config.Aliases, seq AliasSequence, lookup AliasLookup, pool AliasPool, logger logger.Logger) (AliasGenerator, error) {
+	const op = "internal/services/urlservice/newAliasGenerator"
+
+	alphabet := []rune(cfg.Alphabet)
+	unique := make(map[rune]struct{}, len(alphabet))
+
+	for _, r := range alphabet {
+		unique[r] = struct{}{}
+	}
+
+	if len(unique) < 2 || len(unique) != len(alphabet) || cfg.Length < 1 {
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidAliasGenerator)
+	}
+
+	random := RandomGenerator{
+		alphabet: alphabet,
+		length:   cfg.Length,
+	}
+
+	switch cfg.Generator {
+	case "", GeneratorRandom:
+		return random, nil
+	case GeneratorSequence:
+		if seq == (AliasSequence)(nil) {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+		}
+
+		offset := int64(1)
+
+		for range cfg.Length - 1 {
+			offset *= int64(len(alphabet))
+		}
+
+		return SequenceGenerator{
+			seq:      seq,
+			alphabet: alphabet,
+			offset:   offset - 1,
+		}, nil
+	case GeneratorPool:
+		if pool == (AliasPool)(nil) || lookup == (AliasLookup)(nil) || cfg.PoolSize < 1 {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidAliasGenerator)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		return PoolGenerator{
+			pool:      pool,
+			lookup:    lookup,
+			random:    random,
+			size:      cfg.PoolSize,
+			refilling: &atomic.Bool{},
+			logger:    logger,
+			mu:        &sync.Mutex{},
+			wg:        &sync.WaitGroup{},
+			ctx:       ctx,
+			cancel:    cancel,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidAliasGenerator)
+}
+
+type RandomGenerator struct {
+	alphabet []rune
+	length   int
+}
+
+func (g RandomGenerator) Next(_ context.Context, attempt int) (string, error) {
+	length := g.length + max(attempt-1, 0)/attemptsPerExtraRune
+	out := make([]rune, 0, length)
+
+	for range length {
+		out = append(out, g.alphabet[rand.IntN(len(g.alphabet))])
+	}
+
+	return string(out), nil
+}
+
+func (g RandomGenerator) Close() chan error {
+	return closed()
+}
+
+func (g RandomGenerator) ContextInfo() string {
+	return "random alias generator"
+}
+
+type SequenceGenerator struct {
+	seq      AliasSequence
+	alphabet []rune
+	offset   int64
+}
+
+// Next ignores attempt: every call takes a new sequence value, and collisions
+// only happen with custom aliases that are skipped on the next call.
+func (g SequenceGenerator) Next(ctx context.Context, _ int) (string, error) {
+	const op = "internal/services/urlservice/SequenceGenerator.Next"
+
+	value, err := g.seq.NextAliasSeq(ctx)
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	value += g.offset
+	base := int64(len(g.alphabet))
+	out := make([]rune, 0, 12)
+
+	for value > 0 {
+		out = append(out, g.alphabet[value%base])
+		value /= base
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out), nil
+}
+
+func (g SequenceGenerator) Close() chan error {
+	return closed()
+}
+
+func (g SequenceGenerator) ContextInfo() string {
+	return "sequence alias generator"
+}
+
+type PoolGenerator struct {
+	pool      AliasPool
+	lookup    AliasLookup
+	random    RandomGenerator
+	size      int
+	refilling *atomic.Bool
+	logger    logger.Logger
+
+	mu     *sync.Mutex
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Next pops a pre-generated alias on the first attempt. A popped alias can
+// still collide with a custom alias created after the refill, so retries go
+// straight to the random generator.
+func (g PoolGenerator) Next(ctx context.Context, attempt int) (string, error) {
+	const op = "internal/services/urlservice/PoolGenerator.Next"
+
+	if attempt > 1 {
+		alias, err := g.random.Next(ctx, attempt)
+
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		return alias, nil
+	}
+
+	alias, err := g.pool.PopAlias(ctx)
+
+	switch {
+	case err == nil:
+		g.refill()
+
+		return alias, nil
+	case errors.Is(err, generalerrors.ErrCacheMiss):
+		g.refill()
+	default:
+		g.logger.Error("alias pool", slog.String("error", err.Error()))
+	}
+
+	alias, err = g.random.Next(ctx, attempt)
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return alias, nil
+}
+
+func (g PoolGenerator) refill() {
+	if !g.refilling.CompareAndSwap(false, true) {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.ctx.Err() != nil {
+		g.refilling.Store(false)
+		return
+	}
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+		defer g.refilling.Store(false)
+
+		ctx, cancel := context.WithTimeout(g.ctx, defaultTimeoutForRefill)
+		defer cancel()
+
+		size, err := g.pool.PoolSize(ctx)
+
+		if err != nil {
+			g.logger.Error("alias pool size", slog.String("error", err.Error()))
+			return
+		}
+
+		if size > int64(g.size/4) {
+			return
+		}
+
+		aliases := make([]string, 0, g.size-int(size))
+
+		for range g.size - int(size) {
+			alias, _ := g.random.Next(ctx, 1)
+			aliases = append(aliases, alias)
+		}
+
+		aliases, err = g.lookup.FreeAliases(ctx, aliases)
+
+		if err != nil {
+			g.logger.Error("alias pool lookup", slog.String("error", err.Error()))
+			return
+		}
+
+		err = g.pool.PushAliases(ctx, aliases)
+
+		if err != nil {
+			g.logger.Error("alias pool refill", slog.String("error", err.Error()))
+			return
+		}
+
+		g.logger.Debug("alias pool refilled", slog.Int("added", len(aliases)))
+	}()
+}
+
+func (g PoolGenerator) Close() chan error {
+	ch := make(chan error, 1)
+
+	g.mu.Lock()
+	g.cancel()
+	g.mu.Unlock()
+
+	go func() {
+		g.wg.Wait()
+		ch <- nil
+	}()
+
+	return ch
+}
+
+func (g PoolGenerator) ContextInfo() string {
+	return "alias pool refill"
+}
+
+type FilteredGenerator struct {
+	AliasGenerator
+	filter AliasFilter
+}
+
+// Next advances attempt on every rejected alias, so random and pool generators
+// grow the length and escape a blocklist that covers the short keyspace.
+func (g FilteredGenerator) Next(ctx context.Context, attempt int) (string, error) {
+	const op = "internal/services/urlservice/FilteredGenerator.Next"
+
+	for range maxFilteredAttempts {
+		alias, err := g.AliasGenerator.Next(ctx, attempt)
+
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		if g.filter.Allowed(alias) {
+			return alias, nil
+		}
+
+		attempt++
+	}
+
+	return "", fmt.Errorf("%s: %w", op, generalerrors.ErrAliasNotSaved)
+}
+
+func closed() chan error {
+	ch := make(chan error, 1)
+	ch <- nil
+
+	return ch
+}
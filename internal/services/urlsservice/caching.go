@@ -3,9 +3,12 @@ package urlsservice
 import (
 	"context"
 	"fmt"
+	"log/slog"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 )
 
-func (service URLService) SaveResponseInCache(ctx context.Context, alias string, response string) error {
+func (service URLService) SaveResponseInCache(ctx context.Context, alias string, response urls.URL) error {
 	const op = "internal/services/urlsservice/SaveResponseInCache"
 
 	err := service.cache.SaveResponseInCache(ctx, alias, response)
@@ -17,13 +20,13 @@ func (service URLService) SaveResponseInCache(ctx context.Context, alias string,
 	return nil
 }
 
-func (service URLService) GetResponseFromCache(ctx context.Context, alias string) (string, error) {
+func (service URLService) GetResponseFromCache(ctx context.Context, alias string) (urls.URL, error) {
 	const op = "internal/services/urlsservice/GetResponseFromCache"
 
 	response, err := service.cache.GetResponseFromCache(ctx, alias)
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return response, nil
@@ -40,3 +43,15 @@ func (service URLService) RemoveResponseFromCache(ctx context.Context, alias str
 
 	return nil
 }
+
+func (service URLService) removeFromCache(ctx context.Context, aliases ...string) {
+	for _, alias := range aliases {
+		err := service.cache.RemoveResponseFromCache(ctx, alias)
+
+		if err != nil {
+			service.logger.Error("cache", slog.String("error", err.Error()), slog.String("alias", alias))
+		}
+	}
+
+	service.invalidator.Invalidate(ctx, aliases...)
+}
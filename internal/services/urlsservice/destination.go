@@ -0,0 +1,310 @@
+package urlsservice
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+var (
+	deniedNetworks = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("127.0.0.0/8"),
+		netip.MustParsePrefix("169.254.0.0/16"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("192.168.0.0/16"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("224.0.0.0/4"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+		netip.MustParsePrefix("::/128"),
+		netip.MustParsePrefix("::1/128"),
+		netip.MustParsePrefix("fc00::/7"),
+		netip.MustParsePrefix("fe80::/10"),
+		netip.MustParsePrefix("ff00::/8"),
+	}
+)
+
+type DestinationChecker interface {
+	Check(ctx context.Context, rawURL string) error
+}
+
+type DestinationPolicy struct {
+	schemes        map[string]struct{}
+	selfHosts      map[string]struct{}
+	allowPrivate   bool
+	resolver       *net.Resolver
+	resolveTimeout time.Duration
+
+	blocklistPath string
+
+	mu      *sync.RWMutex
+	blocked map[string]struct{}
+	modTime time.Time
+
+	logger logger.Logger
+}
+
+// NewDestinationPolicy treats the redirect base-url host as the service's own host,
+// or the http-server address and local host names when no base-url is configured.
+func NewDestinationPolicy(cfg config.Destinations, redirectCfg config.Redirect, serverCfg config.HTTPServer, logger logger.Logger) (*DestinationPolicy, error) {
+	const op = "internal/services/urlservice/NewDestinationPolicy"
+
+	policy := &DestinationPolicy{
+		schemes:        make(map[string]struct{}, len(cfg.Schemes)),
+		selfHosts:      map[string]struct{}{},
+		allowPrivate:   cfg.AllowPrivate,
+		resolver:       net.DefaultResolver,
+		resolveTimeout: cfg.ResolveTimeout,
+		blocklistPath:  cfg.BlocklistPath,
+		mu:             &sync.RWMutex{},
+		blocked:        map[string]struct{}{},
+		logger:         logger,
+	}
+
+	for _, scheme := range cfg.Schemes {
+		policy.schemes[strings.ToLower(strings.TrimSpace(scheme))] = struct{}{}
+	}
+
+	if redirectCfg.BaseURL != "" {
+		base, err := url.Parse(redirectCfg.BaseURL)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		policy.selfHosts[strings.ToLower(base.Hostname())] = struct{}{}
+	} else {
+		policy.addServerHosts(serverCfg.Address)
+	}
+
+	_, err := policy.Reload(context.Background())
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+func (p *DestinationPolicy) Check(ctx context.Context, rawURL string) error {
+	dest, err := url.Parse(rawURL)
+
+	if err != nil {
+		return generalerrors.ErrDestinationScheme
+	}
+
+	if _, ok := p.schemes[strings.ToLower(dest.Scheme)]; !ok {
+		return generalerrors.ErrDestinationScheme
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(dest.Hostname()), ".")
+
+	if host == "" {
+		return generalerrors.ErrDestinationHost
+	}
+
+	if _, ok := p.selfHosts[host]; ok {
+		return generalerrors.ErrDestinationLoop
+	}
+
+	if p.isBlocked(host) {
+		return generalerrors.ErrDestinationBlocked
+	}
+
+	if p.allowPrivate {
+		return nil
+	}
+
+	addrs, err := p.resolve(ctx, host)
+
+	if err != nil {
+		p.logger.Debug("resolve destination", slog.String("host", host), slog.String("error", err.Error()))
+
+		return generalerrors.ErrDestinationHost
+	}
+
+	for _, addr := range addrs {
+		if isDenied(addr) {
+			return generalerrors.ErrDestinationPrivate
+		}
+	}
+
+	return nil
+}
+
+// DialControl is meant for net.Dialer.Control: it rejects connections to denied
+// networks after DNS resolution, so a host cannot rebind between Check and dial.
+func (p *DestinationPolicy) DialControl(network, address string, c syscall.RawConn) error {
+	if p.allowPrivate {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+
+	if err != nil {
+		return err
+	}
+
+	if isDenied(addrPort.Addr()) {
+		return generalerrors.ErrDestinationPrivate
+	}
+
+	return nil
+}
+
+func isDenied(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	for _, network := range deniedNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *DestinationPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+
+	if p.resolveTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, p.resolveTimeout)
+		defer cancel()
+	}
+
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addrs) == 0 {
+		return nil, errors.New("no addresses")
+	}
+
+	return addrs, nil
+}
+
+func (p *DestinationPolicy) addServerHosts(address string) {
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		host = address
+	}
+
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	if addr, err := netip.ParseAddr(host); host != "" && (err != nil || !addr.IsUnspecified()) {
+		p.selfHosts[host] = struct{}{}
+		return
+	}
+
+	for _, local := range []string{"localhost", "127.0.0.1", "::1"} {
+		p.selfHosts[local] = struct{}{}
+	}
+
+	if name, err := os.Hostname(); err == nil && name != "" {
+		p.selfHosts[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+func (p *DestinationPolicy) isBlocked(host string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for domain := host; domain != ""; {
+		if _, ok := p.blocked[domain]; ok {
+			return true
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+
+		if !ok {
+			break
+		}
+
+		domain = parent
+	}
+
+	return false
+}
+
+// Reload rereads the blocklist file when it changed on disk and returns the number of loaded domains.
+// The file is read without holding the lock; only the swap of the parsed set is locked.
+func (p *DestinationPolicy) Reload(ctx context.Context) (int64, error) {
+	const op = "internal/services/urlservice/DestinationPolicy/Reload"
+
+	if p.blocklistPath == "" {
+		return 0, nil
+	}
+
+	info, err := os.Stat(p.blocklistPath)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p.mu.RLock()
+	unchanged := !p.modTime.IsZero() && info.ModTime().Equal(p.modTime)
+	p.mu.RUnlock()
+
+	if unchanged {
+		return 0, nil
+	}
+
+	file, err := os.Open(p.blocklistPath)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer file.Close()
+
+	blocked := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(scanner.Text())), ".")
+
+		if domain == "" || strings.HasPrefix(domain, "#") {
+			continue
+		}
+
+		blocked[domain] = struct{}{}
+	}
+
+	err = scanner.Err()
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p.mu.Lock()
+	p.blocked = blocked
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+
+	p.logger.Info("destination blocklist loaded", slog.Int("domains", len(blocked)))
+
+	return int64(len(blocked)), nil
+}
@@ -0,0 +1,39 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+func (service URLService) SetDestinations(ctx context.Context, alias, ownerUUID string, destinations []urls.Destination, sticky bool) error {
+	const op = "internal/services/urlservice/destinations.go/SetDestinations"
+
+	if len(destinations) == 0 {
+		return fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidDestinations)
+	}
+
+	for _, dest := range destinations {
+		if dest.Weight < 1 {
+			return fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidDestinations)
+		}
+
+		err := service.destinations.Check(ctx, dest.URL)
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err := service.repo.SetDestinations(ctx, alias, ownerUUID, destinations, sticky)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return nil
+}
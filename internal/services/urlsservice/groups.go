@@ -0,0 +1,137 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+func checkGroupKind(kind string) error {
+	switch kind {
+	case urls.GroupTags, urls.GroupFolders:
+		return nil
+	}
+
+	return generalerrors.ErrInvalidGroup
+}
+
+func (service URLService) CreateGroup(ctx context.Context, kind, ownerUUID, name string) (urls.Group, error) {
+	const op = "internal/services/urlservice/groups.go/CreateGroup"
+
+	err := checkGroupKind(kind)
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group, err := service.repo.CreateGroup(ctx, kind, ownerUUID, strings.TrimSpace(name))
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return group, nil
+}
+
+func (service URLService) ListGroups(ctx context.Context, kind, ownerUUID string) ([]urls.Group, error) {
+	const op = "internal/services/urlservice/groups.go/ListGroups"
+
+	err := checkGroupKind(kind)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	out, err := service.repo.ListGroups(ctx, kind, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (service URLService) RenameGroup(ctx context.Context, kind, ownerUUID string, id int64, name string) (urls.Group, error) {
+	const op = "internal/services/urlservice/groups.go/RenameGroup"
+
+	err := checkGroupKind(kind)
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	group, aliases, err := service.repo.RenameGroup(ctx, kind, ownerUUID, id, strings.TrimSpace(name))
+
+	if err != nil {
+		return urls.Group{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, aliases...)
+
+	return group, nil
+}
+
+func (service URLService) DeleteGroup(ctx context.Context, kind, ownerUUID string, id int64) error {
+	const op = "internal/services/urlservice/groups.go/DeleteGroup"
+
+	err := checkGroupKind(kind)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	aliases, err := service.repo.DeleteGroup(ctx, kind, ownerUUID, id)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, aliases...)
+
+	return nil
+}
+
+func (service URLService) SetURLTags(ctx context.Context, alias, ownerUUID string, tags []string) ([]string, error) {
+	const op = "internal/services/urlservice/groups.go/SetURLTags"
+
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	err := service.repo.SetURLTags(ctx, alias, ownerUUID, normalized)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return normalized, nil
+}
+
+func (service URLService) SetURLFolder(ctx context.Context, alias, ownerUUID, folder string) error {
+	const op = "internal/services/urlservice/groups.go/SetURLFolder"
+
+	err := service.repo.SetURLFolder(ctx, alias, ownerUUID, strings.TrimSpace(folder))
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return nil
+}
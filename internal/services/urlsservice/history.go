@@ -0,0 +1,67 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+const (
+	historyLimit = 100
+)
+
+func (service URLService) ListHistory(ctx context.Context, alias, ownerUUID string) ([]urls.Version, error) {
+	const op = "internal/services/urlservice/history.go/ListHistory"
+
+	versions, err := service.repo.ListHistory(ctx, alias, ownerUUID, historyLimit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(versions) > 0 {
+		return versions, nil
+	}
+
+	url, err := service.repo.GetURL(ctx, alias)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if url.OwnerUUID != ownerUUID {
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
+
+	return []urls.Version{}, nil
+}
+
+// RollbackURL restores the URL the alias had before the given version was
+// written: rolling back to version N applies N's old_url.
+func (service URLService) RollbackURL(ctx context.Context, alias, ownerUUID string, version int) (url urls.URL, err error) {
+	const op = "internal/services/urlservice/history.go/RollbackURL"
+
+	target, err := service.repo.VersionURL(ctx, alias, ownerUUID, version)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = service.destinations.Check(ctx, target)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	url, err = service.repo.RollbackURL(ctx, alias, ownerUUID, version)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return url, nil
+}
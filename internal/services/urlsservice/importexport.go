@@ -0,0 +1,110 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+// checkedSource checks each row's destination as the copy reads it and stops
+// at the first rejected row. Results are cached per scheme and host, so a file
+// pointing at one site resolves it once.
+type checkedSource struct {
+	urls.ImportSource
+
+	ctx          context.Context
+	destinations DestinationChecker
+	checked      map[string]error
+	err          error
+}
+
+func (s *checkedSource) Next() bool {
+	if s.err != nil || !s.ImportSource.Next() {
+		return false
+	}
+
+	rawURL := s.ImportSource.URL().URL
+	key := rawURL
+
+	if dest, err := url.Parse(rawURL); err == nil {
+		key = strings.ToLower(dest.Scheme + "://" + dest.Hostname())
+	}
+
+	err, ok := s.checked[key]
+
+	if !ok {
+		err = s.destinations.Check(s.ctx, rawURL)
+		s.checked[key] = err
+	}
+
+	if err != nil {
+		s.err = fmt.Errorf("%w: %w", generalerrors.ErrInvalidImportRow, err)
+
+		return false
+	}
+
+	return true
+}
+
+func (s *checkedSource) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.ImportSource.Err()
+}
+
+// importPolicy defaults an empty conflict policy to skip.
+func importPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return urls.ImportSkip, nil
+	case urls.ImportSkip, urls.ImportOverwrite, urls.ImportFail:
+		return policy, nil
+	}
+
+	return "", generalerrors.ErrInvalidImportPolicy
+}
+
+func (service URLService) ImportURLs(ctx context.Context, ownerUUID string, source urls.ImportSource, policy string) (urls.ImportResult, error) {
+	const op = "internal/services/urlservice/importexport.go/ImportURLs"
+
+	policy, err := importPolicy(policy)
+
+	if err != nil {
+		return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	checked := &checkedSource{
+		ImportSource: source,
+		ctx:          ctx,
+		destinations: service.destinations,
+		checked:      make(map[string]error),
+	}
+
+	result, err := service.repo.ImportURLs(ctx, ownerUUID, checked, policy)
+
+	if err != nil {
+		return urls.ImportResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, result.UpdatedAliases...)
+
+	return result, nil
+}
+
+func (service URLService) ExportURLs(ctx context.Context, ownerUUID string, fn func(url urls.URL) error) error {
+	const op = "internal/services/urlservice/importexport.go/ExportURLs"
+
+	err := service.repo.ExportURLs(ctx, ownerUUID, fn)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package urlsservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+func TestImportPolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "", want: urls.ImportSkip},
+		{in: urls.ImportSkip, want: urls.ImportSkip},
+		{in: urls.ImportOverwrite, want: urls.ImportOverwrite},
+		{in: urls.ImportFail, want: urls.ImportFail},
+		{in: "replace", wantErr: generalerrors.ErrInvalidImportPolicy},
+		{in: "SKIP", wantErr: generalerrors.ErrInvalidImportPolicy},
+	}
+
+	for _, tt := range tests {
+		got, err := importPolicy(tt.in)
+
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("importPolicy(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("importPolicy(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+type sliceSource struct {
+	rows []string
+	read int
+}
+
+func (s *sliceSource) Next() bool {
+	if s.read >= len(s.rows) {
+		return false
+	}
+
+	s.read++
+
+	return true
+}
+
+func (s *sliceSource) URL() urls.URL {
+	return urls.URL{URL: s.rows[s.read-1]}
+}
+
+func (s *sliceSource) Err() error {
+	return nil
+}
+
+type countingChecker struct {
+	calls []string
+}
+
+func (c *countingChecker) Check(_ context.Context, rawURL string) error {
+	c.calls = append(c.calls, rawURL)
+
+	if strings.Contains(rawURL, "blocked") {
+		return generalerrors.ErrDestinationBlocked
+	}
+
+	return nil
+}
+
+func TestCheckedSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      []string
+		wantRows  int
+		wantCalls int
+		wantRead  int
+		wantErr   error
+	}{
+		{
+			name:      "checks each host once",
+			rows:      []string{"https://a.example.com/1", "https://A.example.com/2", "http://a.example.com/3", "https://b.example.com"},
+			wantRows:  4,
+			wantCalls: 3,
+			wantRead:  4,
+		},
+		{
+			name:      "stops at the first rejected row",
+			rows:      []string{"https://a.example.com", "https://blocked.example.com", "https://c.example.com"},
+			wantRows:  1,
+			wantCalls: 2,
+			wantRead:  2,
+			wantErr:   generalerrors.ErrDestinationBlocked,
+		},
+		{
+			name:      "cached rejection",
+			rows:      []string{"https://blocked.example.com/1", "https://blocked.example.com/2"},
+			wantRows:  0,
+			wantCalls: 1,
+			wantRead:  1,
+			wantErr:   generalerrors.ErrDestinationBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &sliceSource{rows: tt.rows}
+			checker := &countingChecker{}
+			checked := &checkedSource{ImportSource: source, ctx: context.Background(), destinations: checker, checked: make(map[string]error)}
+
+			rows := 0
+
+			for checked.Next() {
+				rows++
+			}
+
+			if rows != tt.wantRows || len(checker.calls) != tt.wantCalls || source.read != tt.wantRead {
+				t.Errorf("rows = %d, checks = %d, read = %d, want %d, %d, %d", rows, len(checker.calls), source.read, tt.wantRows, tt.wantCalls, tt.wantRead)
+			}
+
+			err := checked.Err()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, generalerrors.ErrInvalidImportRow) {
+				t.Errorf("Err() = %v, want %v", err, generalerrors.ErrInvalidImportRow)
+			}
+		})
+	}
+}
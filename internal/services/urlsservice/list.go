@@ -0,0 +1,91 @@
+package urlsservice
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+func (service URLService) ListURLs(ctx context.Context, filter urls.ListFilter, cursor string) ([]urls.URL, string, error) {
+	const op = "internal/services/urlservice/ListURLs"
+
+	if filter.SortBy == "" {
+		filter.SortBy = urls.SortByCreated
+	}
+
+	err := decodeCursor(cursor, &filter)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	limit := filter.Limit
+	filter.Limit++
+
+	out, err := service.repo.ListURLs(ctx, filter)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(out) <= limit {
+		return out, "", nil
+	}
+
+	out = out[:limit]
+
+	return out, encodeCursor(filter.SortBy, out[len(out)-1]), nil
+}
+
+func encodeCursor(sortBy string, last urls.URL) string {
+	raw := strconv.Itoa(last.ID)
+
+	if sortBy == urls.SortByClicks {
+		raw = strconv.FormatInt(last.Clicks, 10) + ":" + raw
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string, filter *urls.ListFilter) error {
+	if cursor == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return generalerrors.ErrInvalidCursor
+	}
+
+	id := string(raw)
+
+	if filter.SortBy == urls.SortByClicks {
+		clicks, rest, ok := strings.Cut(id, ":")
+
+		if !ok {
+			return generalerrors.ErrInvalidCursor
+		}
+
+		filter.AfterClicks, err = strconv.ParseInt(clicks, 10, 64)
+
+		if err != nil {
+			return generalerrors.ErrInvalidCursor
+		}
+
+		id = rest
+	}
+
+	filter.AfterID, err = strconv.Atoi(id)
+
+	if err != nil || filter.AfterID <= 0 {
+		return generalerrors.ErrInvalidCursor
+	}
+
+	return nil
+}
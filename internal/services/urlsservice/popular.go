@@ -0,0 +1,69 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+const (
+	popularBucket    = time.Hour
+	popularRetention = time.Duration(time.Hour * 24 * 8)
+)
+
+var popularWindows = map[string]time.Duration{
+	urls.WindowHour: time.Hour,
+	urls.WindowDay:  time.Hour * 24,
+	urls.WindowWeek: time.Hour * 24 * 7,
+}
+
+func (service URLService) SavePopularAliases(ctx context.Context, aliases []urls.PopularAlias) error {
+	const op = "internal/services/urlservice/popular.go/SavePopularAliases"
+
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	err := service.repo.SavePopularAliases(ctx, time.Now().UTC().Truncate(popularBucket), aliases)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (service URLService) PopularAliases(ctx context.Context, ownerUUID, window string, limit int) ([]urls.PopularAlias, error) {
+	const op = "internal/services/urlservice/popular.go/PopularAliases"
+
+	length, ok := popularWindows[window]
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidWindow)
+	}
+
+	since := time.Now().UTC().Truncate(popularBucket).Add(popularBucket - length)
+
+	out, err := service.repo.PopularAliases(ctx, ownerUUID, since, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (service URLService) PrunePopularAliases(ctx context.Context) (int64, error) {
+	const op = "internal/services/urlservice/popular.go/PrunePopularAliases"
+
+	count, err := service.repo.PrunePopularAliases(ctx, time.Now().Add(-popularRetention))
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,64 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+func (service URLService) GetRules(ctx context.Context, alias, ownerUUID string) (urls.URL, error) {
+	const op = "internal/services/urlservice/rules.go/GetRules"
+
+	url, err := service.repo.GetURL(ctx, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if url.OwnerUUID != ownerUUID {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNotAliasOwner)
+	}
+
+	return url, nil
+}
+
+func (service URLService) SetRules(ctx context.Context, alias, ownerUUID string, rules []urls.Rule) ([]urls.Rule, error) {
+	const op = "internal/services/urlservice/rules.go/SetRules"
+
+	normalized := make([]urls.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		rule = urls.Rule{
+			OS:       strings.ToLower(strings.TrimSpace(rule.OS)),
+			Device:   strings.ToLower(strings.TrimSpace(rule.Device)),
+			Language: strings.ToLower(strings.TrimSpace(rule.Language)),
+			Country:  strings.ToUpper(strings.TrimSpace(rule.Country)),
+			URL:      rule.URL,
+		}
+
+		if rule.OS == "" && rule.Device == "" && rule.Language == "" && rule.Country == "" {
+			return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidRules)
+		}
+
+		err := service.destinations.Check(ctx, rule.URL)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		normalized = append(normalized, rule)
+	}
+
+	err := service.repo.SetRules(ctx, alias, ownerUUID, normalized)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	service.removeFromCache(ctx, alias)
+
+	return normalized, nil
+}
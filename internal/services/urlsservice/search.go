@@ -0,0 +1,79 @@
+package urlsservice
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+)
+
+const (
+	maxSearchTerms  = 8
+	maxSearchOffset = 10000
+)
+
+func searchQuery(query string) (string, error) {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(terms) == 0 {
+		return "", generalerrors.ErrInvalidSearchQuery
+	}
+
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+
+	return strings.Join(terms, " & "), nil
+}
+
+func (service URLService) SearchURLs(ctx context.Context, ownerUUID, query string, limit int, cursor string) ([]urls.URL, string, error) {
+	const op = "internal/services/urlservice/search.go/SearchURLs"
+
+	tsquery, err := searchQuery(query)
+
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	offset := 0
+
+	if cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+
+		if err == nil {
+			offset, err = strconv.Atoi(string(raw))
+		}
+
+		if err != nil || offset <= 0 || offset > maxSearchOffset {
+			return nil, "", fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidCursor)
+		}
+	}
+
+	out, err := service.repo.SearchURLs(ctx, urls.SearchFilter{
+		OwnerUUID: ownerUUID,
+		Query:     tsquery,
+		Limit:     limit + 1,
+		Offset:    offset,
+	})
+
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(out) <= limit || offset+limit >= maxSearchOffset {
+		return out[:min(len(out), limit)], "", nil
+	}
+
+	return out[:limit], base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset + limit))), nil
+}
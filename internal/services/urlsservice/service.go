@@ -3,32 +3,80 @@ package urlsservice
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/logger"
 )
 
 type URLRepository interface {
-	SaveAlias(ctx context.Context, url, alias string) (int, error)
-	GetURL(ctx context.Context, alias string) (string, error)
-	DeleteURL(ctx context.Context, alias string) error
-	UpdateURL(ctx context.Context, newURL, alias string) (url string, err error)
-	SendPopAlias(ctx context.Context, alias string, countOfReq int) error
+	SaveAlias(ctx context.Context, url urls.URL) (int, error)
+	SaveAliases(ctx context.Context, batch []urls.URL) ([]urls.SaveResult, error)
+	GetURL(ctx context.Context, alias string) (urls.URL, error)
+	DeleteURL(ctx context.Context, alias, ownerUUID string) error
+	UpdateURL(ctx context.Context, newURL, alias, ownerUUID string, activation *urls.Activation) (url urls.URL, err error)
+	SavePopularAliases(ctx context.Context, bucket time.Time, aliases []urls.PopularAlias) error
+	PopularAliases(ctx context.Context, ownerUUID string, since time.Time, limit int) ([]urls.PopularAlias, error)
+	PrunePopularAliases(ctx context.Context, before time.Time) (int64, error)
+	ListURLs(ctx context.Context, filter urls.ListFilter) ([]urls.URL, error)
+	SearchURLs(ctx context.Context, filter urls.SearchFilter) ([]urls.URL, error)
+	ImportURLs(ctx context.Context, ownerUUID string, source urls.ImportSource, policy string) (urls.ImportResult, error)
+	ExportURLs(ctx context.Context, ownerUUID string, fn func(url urls.URL) error) error
+	PurgeExpired(ctx context.Context, grace time.Duration, archive bool) (int64, error)
+	ConsumeClick(ctx context.Context, alias string) (int, error)
+	PeekClick(ctx context.Context, alias string) (int, error)
+	CreateGroup(ctx context.Context, kind, ownerUUID, name string) (urls.Group, error)
+	ListGroups(ctx context.Context, kind, ownerUUID string) ([]urls.Group, error)
+	RenameGroup(ctx context.Context, kind, ownerUUID string, id int64, name string) (urls.Group, []string, error)
+	DeleteGroup(ctx context.Context, kind, ownerUUID string, id int64) ([]string, error)
+	SetURLTags(ctx context.Context, alias, ownerUUID string, tags []string) error
+	SetURLFolder(ctx context.Context, alias, ownerUUID, folder string) error
+	SetDestinations(ctx context.Context, alias, ownerUUID string, destinations []urls.Destination, sticky bool) error
+	SetRules(ctx context.Context, alias, ownerUUID string, rules []urls.Rule) error
+	ListHistory(ctx context.Context, alias, ownerUUID string, limit int) ([]urls.Version, error)
+	VersionURL(ctx context.Context, alias, ownerUUID string, version int) (string, error)
+	RollbackURL(ctx context.Context, alias, ownerUUID string, version int) (urls.URL, error)
+	ListTrash(ctx context.Context, ownerUUID string, limit int) ([]urls.URL, error)
+	RestoreURL(ctx context.Context, alias, ownerUUID string) (urls.URL, error)
+	PurgeDeleted(ctx context.Context, quarantine time.Duration) (int64, error)
+	SetActivation(ctx context.Context, alias, ownerUUID string, activeFrom *time.Time, comingSoon bool) (urls.URL, error)
 }
 
 type URLCache interface {
-	SaveResponseInCache(ctx context.Context, alias string, response string) error
-	GetResponseFromCache(ctx context.Context, alias string) (string, error)
+	SaveResponseInCache(ctx context.Context, alias string, response urls.URL) error
+	GetResponseFromCache(ctx context.Context, alias string) (urls.URL, error)
 	RemoveResponseFromCache(ctx context.Context, alias string) error
+	DecrClicksLeft(ctx context.Context, alias string, initial int) (int64, error)
+	ResetClicksLeft(ctx context.Context, alias string, value int) error
+}
+
+type ClickTracker interface {
+	Track(click clicks.Click)
+}
+
+type EventNotifier interface {
+	Notify(event webhooks.Event)
+}
+
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, aliases ...string)
 }
 
 type URLService struct {
-	repo   URLRepository
-	cache  URLCache
-	logger logger.Logger
+	repo         URLRepository
+	cache        URLCache
+	tracker      ClickTracker
+	generator    AliasGenerator
+	destinations DestinationChecker
+	notifier     EventNotifier
+	invalidator  CacheInvalidator
+	logger       logger.Logger
 }
 
-func New(repo URLRepository, cache URLCache, logger logger.Logger) (URLService, error) {
+func New(repo URLRepository, cache URLCache, tracker ClickTracker, generator AliasGenerator, destinations DestinationChecker, notifier EventNotifier, invalidator CacheInvalidator, logger logger.Logger) (URLService, error) {
 	const op = "internal/services/urlservice/New"
 
 	if repo == (URLRepository)(nil) {
@@ -41,10 +89,40 @@ func New(repo URLRepository, cache URLCache, logger logger.Logger) (URLService,
 
 		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
 	}
+	if tracker == (ClickTracker)(nil) {
+		logger.Error("nil pointer in interface ClickTracker")
+
+		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if generator == (AliasGenerator)(nil) {
+		logger.Error("nil pointer in interface AliasGenerator")
+
+		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if destinations == (DestinationChecker)(nil) {
+		logger.Error("nil pointer in interface DestinationChecker")
+
+		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if notifier == (EventNotifier)(nil) {
+		logger.Error("nil pointer in interface EventNotifier")
+
+		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if invalidator == (CacheInvalidator)(nil) {
+		logger.Error("nil pointer in interface CacheInvalidator")
+
+		return URLService{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
 
 	return URLService{
-		repo:   repo,
-		cache:  cache,
-		logger: logger,
+		repo:         repo,
+		cache:        cache,
+		tracker:      tracker,
+		generator:    generator,
+		destinations: destinations,
+		notifier:     notifier,
+		invalidator:  invalidator,
+		logger:       logger,
 	}, nil
 }
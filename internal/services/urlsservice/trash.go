@@ -0,0 +1,49 @@
+package urlsservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+)
+
+const (
+	trashLimit = 100
+)
+
+func (service URLService) ListTrash(ctx context.Context, ownerUUID string) ([]urls.URL, error) {
+	const op = "internal/services/urlservice/trash.go/ListTrash"
+
+	out, err := service.repo.ListTrash(ctx, ownerUUID, trashLimit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (service URLService) RestoreURL(ctx context.Context, alias, ownerUUID string) (urls.URL, error) {
+	const op = "internal/services/urlservice/trash.go/RestoreURL"
+
+	url, err := service.repo.RestoreURL(ctx, alias, ownerUUID)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return url, nil
+}
+
+func (service URLService) PurgeDeleted(ctx context.Context, quarantine time.Duration) (int64, error) {
+	const op = "internal/services/urlservice/trash.go/PurgeDeleted"
+
+	count, err := service.repo.PurgeDeleted(ctx, quarantine)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -5,31 +5,381 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
-func (service URLService) SaveAlias(ctx context.Context, url, alias string) (int, error) {
+func (service URLService) SaveAlias(ctx context.Context, url urls.URL, password string) (urls.URL, error) {
 	const op = "internal/services/urlservice/SaveAlias"
 
-	res, err := service.repo.SaveAlias(ctx, url, alias)
+	err := service.destinations.Check(ctx, url.URL)
 
 	if err != nil {
-		return res, fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return res, nil
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		url.PasswordHash = string(hash)
+	}
+
+	generate := url.Alias == ""
+
+	for attempt := 1; ; attempt++ {
+		if generate {
+			alias, err := service.generator.Next(ctx, attempt)
+
+			if err != nil {
+				return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+			}
+
+			url.Alias = alias
+		}
+
+		id, err := service.repo.SaveAlias(ctx, url)
+
+		if err == nil {
+			url.ID = id
+
+			return url, nil
+		}
+
+		if !generate || attempt >= maxGenerateAttempts || !errors.Is(err, generalerrors.ErrAliasAlreadyExists) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		service.logger.Warn("generated alias collision", slog.String("alias", url.Alias), slog.Int("attempt", attempt))
+	}
+}
+
+func (service URLService) SaveAliases(ctx context.Context, batch []urls.BatchItem) ([]urls.SaveResult, error) {
+	const op = "internal/services/urlservice/SaveAliases"
+
+	items := make([]urls.URL, 0, len(batch))
+	generate := make([]bool, 0, len(batch))
+	pending := make([]int, 0, len(batch))
+
+	for i, item := range batch {
+		if item.Password != "" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(item.Password), bcrypt.DefaultCost)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			item.URL.PasswordHash = string(hash)
+		}
+
+		items = append(items, item.URL)
+		generate = append(generate, item.URL.Alias == "")
+		pending = append(pending, i)
+	}
+
+	out := make([]urls.SaveResult, len(batch))
+	allowed := pending[:0]
+
+	for _, i := range pending {
+		err := service.destinations.Check(ctx, items[i].URL)
+
+		if err != nil {
+			out[i] = urls.SaveResult{ID: -1, Alias: items[i].Alias, Err: err}
+			continue
+		}
+
+		allowed = append(allowed, i)
+	}
+
+	pending = allowed
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		round := make([]urls.URL, 0, len(pending))
+
+		for _, i := range pending {
+			if generate[i] {
+				alias, err := service.generator.Next(ctx, attempt)
+
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+
+				items[i].Alias = alias
+			}
+
+			round = append(round, items[i])
+		}
+
+		saved, err := service.repo.SaveAliases(ctx, round)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		retry := make([]int, 0)
+
+		for j, result := range saved {
+			i := pending[j]
+
+			switch {
+			case result.Err == nil:
+				out[i] = result
+			case errors.Is(result.Err, generalerrors.ErrAliasAlreadyExists) && generate[i] && attempt < maxGenerateAttempts:
+				retry = append(retry, i)
+			case errors.Is(result.Err, generalerrors.ErrAliasAlreadyExists):
+				out[i] = result
+			default:
+				service.logger.Error("save batch item", slog.String("alias", result.Alias), slog.String("error", result.Err.Error()))
+
+				out[i] = urls.SaveResult{ID: -1, Alias: result.Alias, Err: generalerrors.ErrAliasNotSaved}
+			}
+		}
+
+		pending = retry
+	}
+
+	return out, nil
 }
 
-func (service URLService) GetURL(ctx context.Context, alias string) (string, error) {
+func (service URLService) GetURL(ctx context.Context, alias string) (urls.URL, error) {
 	const op = "internal/services/urlservice/GetURL"
 
+	res, err := service.getURL(ctx, alias, true)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (service URLService) PeekURL(ctx context.Context, alias string) (urls.URL, error) {
+	const op = "internal/services/urlservice/PeekURL"
+
+	res, err := service.getURL(ctx, alias, false)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (service URLService) getURL(ctx context.Context, alias string, consume bool) (urls.URL, error) {
+	const op = "internal/services/urlservice/getURL"
+
+	res, err := service.loadURL(ctx, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = checkActive(res)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.Protected {
+		if res.Expired(time.Now()) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasExpired)
+		}
+
+		return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrPasswordRequired)
+	}
+
+	res, err = service.resolve(ctx, res, consume)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (service URLService) GetProtectedURL(ctx context.Context, alias, password string) (urls.URL, error) {
+	const op = "internal/services/urlservice/GetProtectedURL"
+
+	res, err := service.getProtectedURL(ctx, alias, password, true)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (service URLService) PeekProtectedURL(ctx context.Context, alias, password string) (urls.URL, error) {
+	const op = "internal/services/urlservice/PeekProtectedURL"
+
+	res, err := service.getProtectedURL(ctx, alias, password, false)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func (service URLService) getProtectedURL(ctx context.Context, alias, password string, consume bool) (urls.URL, error) {
+	const op = "internal/services/urlservice/getProtectedURL"
+
+	res, err := service.repo.GetURL(ctx, alias)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = checkActive(res)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.Protected {
+		if res.Expired(time.Now()) {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasExpired)
+		}
+
+		err = bcrypt.CompareHashAndPassword([]byte(res.PasswordHash), []byte(password))
+
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrWrongPassword)
+		}
+	}
+
+	res, err = service.resolve(ctx, res, consume)
+
+	if err != nil {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func checkActive(url urls.URL) error {
+	if !url.Pending(time.Now()) {
+		return nil
+	}
+
+	if url.ComingSoon {
+		return generalerrors.ErrAliasComingSoon
+	}
+
+	return generalerrors.ErrAliasNotActive
+}
+
+func (service URLService) resolve(ctx context.Context, url urls.URL, consume bool) (urls.URL, error) {
+	const op = "internal/services/urlservice/resolve"
+
+	if url.Expired(time.Now()) {
+		return urls.URL{}, fmt.Errorf("%s: %w", op, generalerrors.ErrAliasExpired)
+	}
+
+	if url.MaxClicks != nil && !consume {
+		err := service.peekClick(ctx, url)
+
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return url, nil
+	}
+
+	if url.MaxClicks != nil {
+		err := service.consumeClick(ctx, url)
+
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return url, nil
+}
+
+func (service URLService) consumeClick(ctx context.Context, url urls.URL) error {
+	const op = "internal/services/urlservice/consumeClick"
+
+	initial := 0
+
+	if url.RemainingClicks != nil {
+		initial = *url.RemainingClicks
+	}
+
+	left, err := service.cache.DecrClicksLeft(ctx, url.Alias, initial)
+
+	switch {
+	case err != nil:
+		service.logger.Error("clicks counter", slog.String("error", err.Error()))
+	case left < 0:
+		return fmt.Errorf("%s: %w", op, generalerrors.ErrClicksExhausted)
+	}
+
+	_, err = service.repo.ConsumeClick(ctx, url.Alias)
+
+	if err != nil {
+		if errors.Is(err, generalerrors.ErrClicksExhausted) {
+			e := service.cache.ResetClicksLeft(ctx, url.Alias, 0)
+
+			if e != nil {
+				service.logger.Error("clicks counter", slog.String("error", e.Error()))
+			}
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// peekClick checks the remaining clicks in Postgres without spending one, since the
+// cached url carries the count from when it was cached.
+func (service URLService) peekClick(ctx context.Context, url urls.URL) error {
+	const op = "internal/services/urlservice/peekClick"
+
+	_, err := service.repo.PeekClick(ctx, url.Alias)
+
+	if err != nil {
+		if errors.Is(err, generalerrors.ErrClicksExhausted) {
+			e := service.cache.ResetClicksLeft(ctx, url.Alias, 0)
+
+			if e != nil {
+				service.logger.Error("clicks counter", slog.String("error", e.Error()))
+			}
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (service URLService) loadURL(ctx context.Context, alias string) (urls.URL, error) {
+	const op = "internal/services/urlservice/loadURL"
+
 	res, err := service.cache.GetResponseFromCache(ctx, alias)
 
 	switch err {
 	case nil:
-		service.logger.Info("take from cache", slog.String("res", res))
-		return res, nil
+		if !res.Expired(time.Now()) {
+			service.logger.Info("take from cache", slog.String("res", res.URL))
+			return res, nil
+		}
+
+		e := service.cache.RemoveResponseFromCache(ctx, alias)
+
+		if e != nil {
+			service.logger.Error("cache", slog.String("error", e.Error()))
+		}
 	default:
 		if errors.Is(err, generalerrors.ErrCacheMiss) {
 			service.logger.Info("cache mis", slog.String("error", err.Error()))
@@ -42,7 +392,11 @@ func (service URLService) GetURL(ctx context.Context, alias string) (string, err
 	res, err = service.repo.GetURL(ctx, alias)
 
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if res.Expired(time.Now()) {
+		return res, nil
 	}
 
 	err = service.cache.SaveResponseInCache(ctx, alias, res)
@@ -54,20 +408,16 @@ func (service URLService) GetURL(ctx context.Context, alias string) (string, err
 	return res, nil
 }
 
-func (service URLService) DeleteURL(ctx context.Context, alias string) (err error) {
-	const op = "internal/services/urlservice/GetURL"
+func (service URLService) DeleteURL(ctx context.Context, alias, ownerUUID string) (err error) {
+	const op = "internal/services/urlservice/DeleteURL"
 
 	defer func() {
 		if err == nil {
-			e := service.cache.RemoveResponseFromCache(ctx, alias)
-
-			if e != nil {
-				service.logger.Error("cache", slog.String("error", err.Error()))
-			}
+			service.removeFromCache(ctx, alias)
 		}
 	}()
 
-	err = service.repo.DeleteURL(ctx, alias)
+	err = service.repo.DeleteURL(ctx, alias, ownerUUID)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -76,38 +426,50 @@ func (service URLService) DeleteURL(ctx context.Context, alias string) (err erro
 	return nil
 }
 
-func (service URLService) UpdateURL(ctx context.Context, newURL, alias string) (err error) {
+func (service URLService) UpdateURL(ctx context.Context, newURL, alias, ownerUUID string, activation *urls.Activation) (url urls.URL, err error) {
 	const op = "internal/services/urlservice/UpdateURL"
 
-	var url string
-
-	defer func() {
-		if err == nil {
-			e := service.cache.SaveResponseInCache(ctx, alias, url)
+	if newURL != "" {
+		err = service.destinations.Check(ctx, newURL)
 
-			if e != nil {
-				service.logger.Error("cache", slog.String("error", err.Error()))
-			}
+		if err != nil {
+			return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 		}
-	}()
+	}
 
-	url, err = service.repo.UpdateURL(ctx, newURL, alias)
+	url, err = service.repo.UpdateURL(ctx, newURL, alias, ownerUUID, activation)
 
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return urls.URL{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nil
+	service.removeFromCache(ctx, alias)
+
+	return url, nil
 }
 
-func (service URLService) SendPopAlias(ctx context.Context, alias string, countOfReq int) error {
-	const op = "internal/services/urlservice/urls.go/SendPopAlias"
+func (service URLService) PurgeExpired(ctx context.Context, grace time.Duration, archive bool) (int64, error) {
+	const op = "internal/services/urlservice/PurgeExpired"
 
-	err := service.repo.SendPopAlias(ctx, alias, countOfReq)
+	count, err := service.repo.PurgeExpired(ctx, grace, archive)
 
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return nil
+	return count, nil
+}
+
+// TrackClick records the click; target is the url the visitor was sent to, which
+// click.Destination only holds for rule and variant redirects.
+func (service URLService) TrackClick(click clicks.Click, target string) {
+	service.tracker.Track(click)
+
+	service.notifier.Notify(webhooks.Event{
+		Type:       webhooks.EventURLClicked,
+		Alias:      click.Alias,
+		URL:        target,
+		Referrer:   click.Referrer,
+		OccurredAt: click.ClickedAt,
+	})
 }
@@ -0,0 +1,147 @@
+package webhookservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+)
+
+const (
+	maxResponseBody = 4096
+
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the value of the signature header: an HMAC-SHA256 over "<timestamp>.<body>".
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s Service) Deliver(ctx context.Context) (int64, error) {
+	const op = "internal/services/webhookservice/Deliver"
+
+	tasks, err := s.repo.ClaimDeliveries(ctx, s.cfg.BatchSize, 2*s.cfg.Timeout)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		delivered atomic.Int64
+	)
+
+	for _, task := range tasks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if s.deliver(ctx, task) {
+				delivered.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return delivered.Load(), nil
+}
+
+func (s Service) deliver(ctx context.Context, task webhooks.Task) bool {
+	code, err := s.send(ctx, task)
+
+	if err == nil {
+		e := s.repo.MarkDelivered(ctx, task.ID, code)
+
+		if e != nil {
+			s.logger.Error("mark webhook delivered", slog.Int64("delivery", task.ID), slog.String("error", e.Error()))
+		}
+
+		return true
+	}
+
+	status := webhooks.StatusPending
+
+	if task.Attempts >= s.cfg.MaxAttempts {
+		status = webhooks.StatusDead
+	}
+
+	s.logger.Info("webhook delivery failed", slog.Int64("delivery", task.ID), slog.Int("attempt", task.Attempts), slog.String("status", status), slog.String("error", err.Error()))
+
+	e := s.repo.MarkFailed(ctx, task.ID, status, s.backoff(task.Attempts), code, err.Error())
+
+	if e != nil {
+		s.logger.Error("mark webhook failed", slog.Int64("delivery", task.ID), slog.String("error", e.Error()))
+	}
+
+	return false
+}
+
+func (s Service) send(ctx context.Context, task webhooks.Task) (int, error) {
+	err := s.checker.Check(ctx, task.URL)
+
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.URL, bytes.NewReader(task.Payload))
+
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "url-shorter-webhooks")
+	req.Header.Set(HeaderEvent, task.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(task.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(task.Secret, timestamp, task.Payload))
+
+	resp, err := s.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s Service) backoff(attempt int) time.Duration {
+	delay := s.cfg.BackoffBase
+
+	for i := 1; i < attempt && delay < s.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.cfg.BackoffMax)
+}
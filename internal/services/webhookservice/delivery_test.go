@@ -0,0 +1,77 @@
+package webhookservice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			secret:    "secret",
+			timestamp: 1700000000,
+			body:      `{"event":"url.created"}`,
+			want:      "sha256=bbda9a7f5b6c44499f6360d4b20d1aa66adaac82b25fb97c94299c04cf0c0a8b",
+		},
+		{
+			secret:    "whsec_test",
+			timestamp: 1712345678,
+			body:      "hello",
+			want:      "sha256=7d3ff9643c0cd8be42c5720e1755901219dc82416e61ef9dbcba34fe9ab52f7d",
+		},
+		{
+			secret:    "",
+			timestamp: 0,
+			body:      "",
+			want:      "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %d, %q) = %s, want %s", tt.secret, tt.timestamp, tt.body, got, tt.want)
+		}
+	}
+
+	base := Sign("secret", 1700000000, []byte("body"))
+
+	for name, got := range map[string]string{
+		"secret":    Sign("secret2", 1700000000, []byte("body")),
+		"timestamp": Sign("secret", 1700000001, []byte("body")),
+		"body":      Sign("secret", 1700000000, []byte("body2")),
+	} {
+		if got == base {
+			t.Errorf("Sign() does not depend on the %s", name)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s := Service{cfg: config.Webhooks{BackoffBase: 10 * time.Second, BackoffMax: 5 * time.Minute}}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 10 * time.Second},
+		{attempt: 1, want: 10 * time.Second},
+		{attempt: 2, want: 20 * time.Second},
+		{attempt: 3, want: 40 * time.Second},
+		{attempt: 5, want: 160 * time.Second},
+		{attempt: 6, want: 5 * time.Minute},
+		{attempt: 1000, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := s.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package webhookservice
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+)
+
+const (
+	DefaultTimeoutForEnqueue = time.Duration(time.Second * 2)
+	DefaultEnqueueInterval   = time.Duration(time.Second)
+)
+
+// Notify buffers a click event. Lifecycle events do not go through here: they
+// are written to the outbox in the mutating transaction and reach Publish.
+func (s Service) Notify(event webhooks.Event) {
+	if !s.subscribed.Load() {
+		return
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	select {
+	case <-s.stop:
+		s.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+func (s Service) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(DefaultEnqueueInterval)
+		defer ticker.Stop()
+
+		s.refreshSubscribers()
+
+		batch := make([]webhooks.Event, 0, s.cfg.BatchSize)
+
+		for {
+			select {
+			case event := <-s.events:
+				batch = append(batch, event)
+
+				if len(batch) >= s.cfg.BatchSize {
+					batch = s.enqueue(batch)
+				}
+			case <-ticker.C:
+				batch = s.enqueue(batch)
+				s.refreshSubscribers()
+			case <-s.stop:
+				for {
+					select {
+					case event := <-s.events:
+						batch = append(batch, event)
+
+						if len(batch) >= s.cfg.BatchSize {
+							batch = s.enqueue(batch)
+						}
+					default:
+						s.enqueue(batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (s Service) enqueue(batch []webhooks.Event) []webhooks.Event {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	payloads := make([][]byte, 0, len(batch))
+
+	for _, event := range batch {
+		payload, err := json.Marshal(event)
+
+		if err != nil {
+			s.logger.Error("json marshal", slog.String("error", err.Error()))
+
+			payload = []byte("{}")
+		}
+
+		payloads = append(payloads, payload)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeoutForEnqueue)
+	defer cancel()
+
+	err := s.repo.EnqueueDeliveries(ctx, batch, payloads)
+
+	if err != nil {
+		s.logger.Error("enqueue webhook deliveries", slog.String("error", err.Error()), slog.Int("lost", len(batch)))
+	}
+
+	if dropped := s.dropped.Swap(0); dropped > 0 {
+		s.logger.Warn("webhook events dropped, buffer is full", slog.Int64("dropped", dropped))
+	}
+
+	return batch[:0]
+}
+
+func (s Service) refreshSubscribers() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeoutForEnqueue)
+	defer cancel()
+
+	ok, err := s.repo.HasSubscribers(ctx, webhooks.EventURLClicked)
+
+	if err != nil {
+		s.logger.Error("check click subscribers", slog.String("error", err.Error()))
+
+		return
+	}
+
+	s.subscribed.Store(ok)
+}
+
+func (s Service) Close() chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		close(s.stop)
+		<-s.done
+		ch <- nil
+	}()
+
+	return ch
+}
+
+func (s Service) ContextInfo() string {
+	return "webhook notifier"
+}
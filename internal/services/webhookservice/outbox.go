@@ -0,0 +1,76 @@
+package webhookservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Cwby333/url-shorter/internal/entity/outbox"
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+)
+
+var outboxEvents = map[string]string{
+	outbox.EventURLCreated:  webhooks.EventURLCreated,
+	outbox.EventURLUpdated:  webhooks.EventURLUpdated,
+	outbox.EventURLRestored: webhooks.EventURLUpdated,
+	outbox.EventURLDeleted:  webhooks.EventURLDeleted,
+	outbox.EventURLPurged:   webhooks.EventURLDeleted,
+}
+
+type urlPayload struct {
+	Alias     string `json:"alias"`
+	URL       string `json:"url"`
+	OwnerUUID string `json:"owner_uuid"`
+}
+
+// Name and Publish make the service an outbox sink, so lifecycle events are
+// queued for delivery only once their transaction has committed.
+func (s Service) Name() string {
+	return "webhook subscriptions"
+}
+
+func (s Service) Publish(ctx context.Context, event outbox.Event) error {
+	const op = "internal/services/webhookservice/Publish"
+
+	if event.Aggregate != outbox.AggregateURL {
+		return nil
+	}
+
+	kind, ok := outboxEvents[event.Type]
+
+	if !ok {
+		return nil
+	}
+
+	var url urlPayload
+
+	err := json.Unmarshal(event.Payload, &url)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if url.OwnerUUID == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhooks.Event{
+		ID:         event.EventID,
+		Type:       kind,
+		Alias:      url.Alias,
+		URL:        url.URL,
+		OccurredAt: event.CreatedAt,
+	})
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = s.repo.EnqueueEvent(ctx, url.OwnerUUID, event.EventID, kind, payload)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
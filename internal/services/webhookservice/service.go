@@ -0,0 +1,181 @@
+package webhookservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/logger"
+)
+
+const (
+	deliveriesLimit = 100
+	secretSize      = 32
+)
+
+type Repository interface {
+	CreateWebhook(ctx context.Context, ownerUUID string, hook webhooks.Webhook) (webhooks.Webhook, error)
+	ListWebhooks(ctx context.Context, ownerUUID string) ([]webhooks.Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerUUID string, id int64) error
+	ListDeliveries(ctx context.Context, ownerUUID string, id int64, status string, limit int) ([]webhooks.Delivery, error)
+	EnqueueDeliveries(ctx context.Context, events []webhooks.Event, payloads [][]byte) error
+	EnqueueEvent(ctx context.Context, ownerUUID, eventID, event string, payload []byte) error
+	HasSubscribers(ctx context.Context, event string) (bool, error)
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhooks.Task, error)
+	MarkDelivered(ctx context.Context, id int64, code int) error
+	MarkFailed(ctx context.Context, id int64, status string, retryIn time.Duration, code int, reason string) error
+}
+
+type URLChecker interface {
+	Check(ctx context.Context, rawURL string) error
+	DialControl(network, address string, c syscall.RawConn) error
+}
+
+type Service struct {
+	repo    Repository
+	checker URLChecker
+	client  *http.Client
+	logger  logger.Logger
+	cfg     config.Webhooks
+
+	events     chan webhooks.Event
+	dropped    *atomic.Int64
+	subscribed *atomic.Bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func New(repo Repository, checker URLChecker, logger logger.Logger, cfg config.Webhooks) (Service, error) {
+	const op = "internal/services/webhookservice/New"
+
+	if repo == (Repository)(nil) {
+		logger.Error("nil pointer in interface Repository")
+
+		return Service{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+	if checker == (URLChecker)(nil) {
+		logger.Error("nil pointer in interface URLChecker")
+
+		return Service{}, fmt.Errorf("%s: %w", op, generalerrors.ErrNilPointerInInterface)
+	}
+
+	subscribed := &atomic.Bool{}
+	subscribed.Store(true)
+
+	return Service{
+		repo:       repo,
+		checker:    checker,
+		client:     NewClient(cfg.Timeout, checker.DialControl),
+		logger:     logger,
+		cfg:        cfg,
+		events:     make(chan webhooks.Event, cfg.BufferSize),
+		dropped:    &atomic.Int64{},
+		subscribed: subscribed,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}, nil
+}
+
+// NewClient returns a client that does not follow redirects or use a proxy and
+// lets control veto every address it actually dials.
+func NewClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}).DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (s Service) CreateWebhook(ctx context.Context, ownerUUID, rawURL string, events []string) (webhooks.Webhook, error) {
+	const op = "internal/services/webhookservice/CreateWebhook"
+
+	events = slices.Compact(slices.Sorted(slices.Values(events)))
+
+	for _, event := range events {
+		if !slices.Contains(webhooks.Events, event) {
+			return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidWebhookEvents)
+		}
+	}
+
+	err := s.checker.Check(ctx, rawURL)
+
+	if err != nil {
+		return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret := make([]byte, secretSize)
+
+	_, err = rand.Read(secret)
+
+	if err != nil {
+		return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hook, err := s.repo.CreateWebhook(ctx, ownerUUID, webhooks.Webhook{
+		URL:    rawURL,
+		Events: events,
+		Secret: hex.EncodeToString(secret),
+	})
+
+	if err != nil {
+		return webhooks.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hook, nil
+}
+
+func (s Service) ListWebhooks(ctx context.Context, ownerUUID string) ([]webhooks.Webhook, error) {
+	const op = "internal/services/webhookservice/ListWebhooks"
+
+	out, err := s.repo.ListWebhooks(ctx, ownerUUID)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (s Service) DeleteWebhook(ctx context.Context, ownerUUID string, id int64) error {
+	const op = "internal/services/webhookservice/DeleteWebhook"
+
+	err := s.repo.DeleteWebhook(ctx, ownerUUID, id)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s Service) ListDeliveries(ctx context.Context, ownerUUID string, id int64, status string) ([]webhooks.Delivery, error) {
+	const op = "internal/services/webhookservice/ListDeliveries"
+
+	out, err := s.repo.ListDeliveries(ctx, ownerUUID, id, status, deliveriesLimit)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package pages
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+type Page struct {
+	Title   string
+	Message string
+}
+
+var (
+	NotFound = Page{
+		Title:   "404 Not Found",
+		Message: "This short link does not exist.",
+	}
+	Gone = Page{
+		Title:   "410 Gone",
+		Message: "This short link is no longer available.",
+	}
+	ComingSoon = Page{
+		Title:   "Coming soon",
+		Message: "This short link is not active yet, please check back later.",
+	}
+	InternalError = Page{
+		Title:   "500 Internal Server Error",
+		Message: "Something went wrong, please try again later.",
+	}
+)
+
+var messageTemplate = template.Must(template.New("message").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</body>
+</html>
+`))
+
+var passwordTemplate = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Password required</title>
+</head>
+<body>
+<h1>Password required</h1>
+<p>This short link is protected. Enter the password to continue.</p>
+{{if .}}<p role="alert">{{.}}</p>{{end}}
+<form method="post">
+<input type="password" name="password" autocomplete="current-password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+func WritePasswordForm(w http.ResponseWriter, status int, message string) error {
+	const op = "internal/transport/http/lib/pages/WritePasswordForm"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	err := passwordTemplate.Execute(w, message)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func Write(w http.ResponseWriter, status int, page Page) error {
+	const op = "internal/transport/http/lib/pages/Write"
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	err := messageTemplate.Execute(w, page)
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -18,6 +18,46 @@ func Validate(errors validator.ValidationErrors) []string {
 		case "url":
 			str := "invalid url"
 
+			out = append(out, str)
+		case "required_without":
+			str := fmt.Sprintf("field %s is required when %s is not set", err.Field(), err.Param())
+
+			out = append(out, str)
+		case "excluded_with":
+			str := fmt.Sprintf("field %s cannot be used together with %s", err.Field(), err.Param())
+
+			out = append(out, str)
+		case "min":
+			str := fmt.Sprintf("field %s must be at least %s", err.Field(), err.Param())
+
+			out = append(out, str)
+		case "max":
+			str := fmt.Sprintf("field %s must be at most %s", err.Field(), err.Param())
+
+			out = append(out, str)
+		case "oneof":
+			str := fmt.Sprintf("field %s must be one of: %s", err.Field(), err.Param())
+
+			out = append(out, str)
+		case "bcp47_language_tag":
+			str := fmt.Sprintf("field %s must be a language tag like en or de-AT", err.Field())
+
+			out = append(out, str)
+		case "iso3166_1_alpha2":
+			str := fmt.Sprintf("field %s must be a two-letter country code", err.Field())
+
+			out = append(out, str)
+		case "alias_charset":
+			str := fmt.Sprintf("field %s contains characters that are not allowed", err.Field())
+
+			out = append(out, str)
+		case "alias_reserved":
+			str := fmt.Sprintf("field %s is a reserved word", err.Field())
+
+			out = append(out, str)
+		case "alias_blocked":
+			str := fmt.Sprintf("field %s contains a blocked word", err.Field())
+
 			out = append(out, str)
 		}
 	}
@@ -128,6 +128,7 @@ func NewAccess(next http.Handler) http.Handler {
 
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, "logger", logger)
+		ctx = context.WithValue(ctx, "claims", claims)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
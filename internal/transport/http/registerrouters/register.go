@@ -5,18 +5,25 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/Cwby333/url-shorter/internal/aliaspolicy"
+	"github.com/Cwby333/url-shorter/internal/config"
 	"github.com/Cwby333/url-shorter/internal/logger"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/limitermidde"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/logging"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/recovermiddle"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/requestid"
 	"github.com/Cwby333/url-shorter/internal/transport/http/ratelimiter"
 	"github.com/Cwby333/url-shorter/internal/transport/http/urlrouter"
 	"github.com/Cwby333/url-shorter/internal/transport/http/usersrouter"
+	"github.com/Cwby333/url-shorter/internal/transport/http/webhookrouter"
 )
 
-func New(urlService urlrouter.URLService, logger logger.Logger, usersService usersrouter.UsersService, limiter ratelimiter.Limiter, mainCtx context.Context) (*http.ServeMux, error) {
+func New(urlService urlrouter.URLService, statsService urlrouter.StatsService, qrService urlrouter.QRService, aliasPolicy *aliaspolicy.Policy, geo urlrouter.GeoLocator, logger logger.Logger, usersService usersrouter.UsersService, webhookService webhookrouter.WebhookService, limiter ratelimiter.Limiter, redirectCfg config.Redirect, mainCtx context.Context) (*http.ServeMux, error) {
 	const op = "internal/transports/httptransport/registerrouters/register.go/Register"
 
 	mux := http.NewServeMux()
 
-	routerURLS, err := urlrouter.New(urlService, logger, limiter, mainCtx)
+	routerURLS, err := urlrouter.New(urlService, statsService, qrService, aliasPolicy, geo, logger, limiter, redirectCfg, mainCtx)
 
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -32,8 +39,24 @@ func New(urlService urlrouter.URLService, logger logger.Logger, usersService use
 
 	routerUsers.Run()
 
+	routerWebhooks, err := webhookrouter.New(webhookService, logger.Logger, limiter)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	routerWebhooks.Run()
+
 	mux.Handle("/api/urls/", http.StripPrefix("/api/urls", routerURLS.Router))
 	mux.Handle("/api/users/", http.StripPrefix("/api/users", routerUsers.Router))
+	mux.Handle("/api/webhooks/", http.StripPrefix("/api/webhooks", routerWebhooks.Router))
+
+	aliasPolicy.ReservePattern("/api/urls/")
+	aliasPolicy.ReservePattern("/api/users/")
+	aliasPolicy.ReservePattern("/api/webhooks/")
+
+	mux.Handle("GET /{alias}", recovermiddle.New(requestid.New(logger.Logger)(logging.New(limitermidde.New(limiter)(http.HandlerFunc(routerURLS.Redirect))))))
+	mux.Handle("POST /{alias}", recovermiddle.New(requestid.New(logger.Logger)(logging.New(limitermidde.New(limiter)(http.HandlerFunc(routerURLS.Unlock))))))
 
 	return mux, nil
 }
@@ -7,22 +7,24 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 
+	"github.com/Cwby333/url-shorter/internal/aliaspolicy"
 	"github.com/Cwby333/url-shorter/internal/config"
 	"github.com/Cwby333/url-shorter/internal/logger"
 	"github.com/Cwby333/url-shorter/internal/transport/http/ratelimiter"
 	"github.com/Cwby333/url-shorter/internal/transport/http/registerrouters"
 	"github.com/Cwby333/url-shorter/internal/transport/http/urlrouter"
 	"github.com/Cwby333/url-shorter/internal/transport/http/usersrouter"
+	"github.com/Cwby333/url-shorter/internal/transport/http/webhookrouter"
 )
 
 type Server struct {
 	Server *http.Server
 }
 
-func New(ctx context.Context, cfg config.HTTPServer, urlService urlrouter.URLService, logger logger.Logger, userService usersrouter.UsersService, limiter ratelimiter.Limiter, mainCtx context.Context) (Server, error) {
+func New(ctx context.Context, cfg config.HTTPServer, urlService urlrouter.URLService, statsService urlrouter.StatsService, qrService urlrouter.QRService, aliasPolicy *aliaspolicy.Policy, geo urlrouter.GeoLocator, logger logger.Logger, userService usersrouter.UsersService, webhookService webhookrouter.WebhookService, limiter ratelimiter.Limiter, redirectCfg config.Redirect, mainCtx context.Context) (Server, error) {
 	const op = "transport/http/httpserver/New"
 
-	mux, err := registerrouters.New(urlService, logger, userService, limiter, mainCtx)
+	mux, err := registerrouters.New(urlService, statsService, qrService, aliasPolicy, geo, logger, userService, webhookService, limiter, redirectCfg, mainCtx)
 
 	if err != nil {
 		return Server{}, fmt.Errorf("%s:%w", op, err)
@@ -0,0 +1,218 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+	validaterequests "github.com/Cwby333/url-shorter/internal/transport/http/lib/validaterequsts"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxBatchItems    = 500
+	maxBatchBodySize = 4 << 20
+
+	// every password is hashed with bcrypt, so protected items are capped
+	// separately to bound the CPU a single request can burn
+	maxProtectedBatchItems = 20
+)
+
+type ResponseBatchItem struct {
+	Index int    `json:"index"`
+	ID    int    `json:"id"`
+	Alias string `json:"alias,omitempty"`
+	mainresponse.Response
+}
+
+type ResponseBatch struct {
+	Results []ResponseBatchItem `json:"results"`
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	mainresponse.Response
+}
+
+func newBatchResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/batch.go/newBatchResponse"
+
+	response := ResponseBatch{
+		Results:  []ResponseBatchItem{},
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (router *Router) Batch(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "batch handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	reqs := []RequestSave{}
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodySize)).Decode(&reqs)
+
+	if err == nil && (len(reqs) == 0 || len(reqs) > maxBatchItems) {
+		err = fmt.Errorf("batch must contain between 1 and %d items", maxBatchItems)
+	}
+
+	if err == nil && protectedItems(reqs) > maxProtectedBatchItems {
+		err = fmt.Errorf("batch may contain at most %d password protected items", maxProtectedBatchItems)
+	}
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		out, err := newBatchResponse(err)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusBadRequest)
+		return
+	}
+
+	r.Body.Close()
+
+	now := time.Now()
+	results := make([]ResponseBatchItem, len(reqs))
+	items := make([]urls.BatchItem, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+
+	for i, req := range reqs {
+		results[i] = ResponseBatchItem{
+			Index: i,
+			ID:    -1,
+		}
+
+		err = router.validator.Struct(req)
+
+		if err != nil {
+			results[i].Response = mainresponse.NewError(validaterequests.Validate(err.(validator.ValidationErrors))...)
+			continue
+		}
+
+		url, err := req.toURL(sub, now)
+
+		if err != nil {
+			results[i].Response = mainresponse.NewError(err.Error())
+			continue
+		}
+
+		results[i].Alias = url.Alias
+
+		items = append(items, urls.BatchItem{
+			URL:      url,
+			Password: req.Password,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(items) > 0 {
+		saved, err := router.urlService.SaveAliases(r.Context(), items)
+
+		if err != nil {
+			logger.Error("batch handler", slog.String("error", err.Error()))
+
+			out, err := newBatchResponse(errors.New(respforusers.ErrInternalError))
+
+			if err != nil {
+				logger.Error("json marshal", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusInternalServerError)
+			return
+		}
+
+		for i, result := range saved {
+			item := &results[indexes[i]]
+			item.Alias = result.Alias
+
+			if result.Err != nil {
+				item.Response = mainresponse.NewError(result.Err.Error())
+				continue
+			}
+
+			item.ID = result.ID
+			item.Response = mainresponse.NewOK()
+		}
+	}
+
+	response := ResponseBatch{
+		Results:  results,
+		Response: mainresponse.NewOK(),
+	}
+
+	for _, result := range results {
+		if result.ID < 0 {
+			response.Failed++
+		} else {
+			response.Created++
+		}
+	}
+
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success batch handler", slog.Int("created", response.Created), slog.Int("failed", response.Failed))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
+
+func protectedItems(reqs []RequestSave) int {
+	count := 0
+
+	for _, req := range reqs {
+		if req.Password != "" {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,19 @@
+package urlrouter
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func subject(r *http.Request) (string, bool) {
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+
+	if !ok {
+		return "", false
+	}
+
+	sub, ok := claims["sub"].(string)
+
+	return sub, ok
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
@@ -50,6 +51,14 @@ func (router *Router) Delete(w http.ResponseWriter, r *http.Request) {
 
 	logger = logger.With("component", "delete handler")
 
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
 	req := RequestDelete{}
 	err = json.NewDecoder(r.Body).Decode(&req)
 
@@ -113,9 +122,44 @@ func (router *Router) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = router.urlService.DeleteURL(r.Context(), req.Alias)
+	err = router.urlService.DeleteURL(r.Context(), req.Alias, sub)
 
 	if err != nil {
+		if errors.Is(err, generalerrors.ErrAliasNotFound) {
+			logger.Debug("delete alias handler", slog.String("error", err.Error()))
+
+			out, err := newDeleteResponse(generalerrors.ErrAliasNotFound)
+
+			if err != nil {
+				logger.Error("json marshall", slog.String("error", err.Error()))
+
+				http.Error(w, "alias not found", http.StatusNotFound)
+
+				return
+			}
+
+			http.Error(w, string(out), http.StatusNotFound)
+
+			return
+		}
+		if errors.Is(err, generalerrors.ErrNotAliasOwner) {
+			logger.Info("delete alias handler", slog.String("error", err.Error()))
+
+			out, err := newDeleteResponse(generalerrors.ErrNotAliasOwner)
+
+			if err != nil {
+				logger.Error("json marshall", slog.String("error", err.Error()))
+
+				http.Error(w, "forbidden", http.StatusForbidden)
+
+				return
+			}
+
+			http.Error(w, string(out), http.StatusForbidden)
+
+			return
+		}
+
 		logger.Error("delete alias handler", slog.String("error", err.Error()))
 
 		out, err := newDeleteResponse(errors.New("internal error"))
@@ -0,0 +1,116 @@
+package urlrouter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+)
+
+var destinationErrors = []error{
+	generalerrors.ErrDestinationScheme,
+	generalerrors.ErrDestinationHost,
+	generalerrors.ErrDestinationPrivate,
+	generalerrors.ErrDestinationLoop,
+	generalerrors.ErrDestinationBlocked,
+}
+
+type RequestDestination struct {
+	URL    string `json:"url" validate:"required,url"`
+	Weight int    `json:"weight" validate:"required,min=1,max=1000"`
+}
+
+type RequestDestinations struct {
+	Destinations []RequestDestination `json:"destinations" validate:"required,min=1,max=10,dive"`
+	Sticky       bool                 `json:"sticky"`
+}
+
+type ResponseDestinations struct {
+	Alias        string             `json:"alias"`
+	Destinations []urls.Destination `json:"destinations"`
+	Sticky       bool               `json:"sticky"`
+	mainresponse.Response
+}
+
+func destinationError(err error) error {
+	for _, target := range destinationErrors {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+
+	return nil
+}
+
+func writeDestinationsError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	if target := destinationError(err); target != nil {
+		logger.Info("set destinations", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusBadRequest, target)
+		return
+	}
+
+	if errors.Is(err, generalerrors.ErrInvalidDestinations) {
+		logger.Info("set destinations", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidDestinations)
+		return
+	}
+
+	if errors.Is(err, generalerrors.ErrAliasNotFound) {
+		logger.Debug("set destinations", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusNotFound, generalerrors.ErrAliasNotFound)
+		return
+	}
+
+	if errors.Is(err, generalerrors.ErrNotAliasOwner) {
+		logger.Info("set destinations", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusForbidden, generalerrors.ErrNotAliasOwner)
+		return
+	}
+
+	logger.Error("set destinations", slog.String("error", err.Error()))
+
+	writeGroupError(w, logger, http.StatusInternalServerError, errors.New(respforusers.ErrInternalError))
+}
+
+func (router *Router) SetDestinations(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "set destinations handler")
+
+	if !ok {
+		return
+	}
+
+	req := RequestDestinations{}
+
+	if !router.decodeGroupRequest(w, r, logger, &req) {
+		return
+	}
+
+	destinations := make([]urls.Destination, 0, len(req.Destinations))
+
+	for _, destination := range req.Destinations {
+		destinations = append(destinations, urls.Destination{URL: destination.URL, Weight: destination.Weight})
+	}
+
+	alias := r.PathValue("alias")
+	err := router.urlService.SetDestinations(r.Context(), alias, sub, destinations, req.Sticky)
+
+	if err != nil {
+		writeDestinationsError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseDestinations{
+		Alias:        alias,
+		Destinations: destinations,
+		Sticky:       req.Sticky,
+		Response:     mainresponse.NewOK(),
+	})
+}
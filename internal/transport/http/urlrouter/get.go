@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
@@ -18,7 +19,8 @@ import (
 )
 
 type RequestGet struct {
-	Alias string `json:"alias" validate:"required"`
+	Alias    string `json:"alias" validate:"required"`
+	Password string `json:"password,omitempty"`
 }
 
 type ResponseGet struct {
@@ -110,10 +112,44 @@ func (router *Router) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url, err := router.urlService.GetURL(r.Context(), req.Alias)
+	var url urls.URL
+
+	head := r.Method == http.MethodHead
+
+	switch {
+	case req.Password != "" && head:
+		url, err = router.urlService.PeekProtectedURL(r.Context(), req.Alias, req.Password)
+	case req.Password != "":
+		url, err = router.urlService.GetProtectedURL(r.Context(), req.Alias, req.Password)
+	case head:
+		url, err = router.urlService.PeekURL(r.Context(), req.Alias)
+	default:
+		url, err = router.urlService.GetURL(r.Context(), req.Alias)
+	}
 
 	if err != nil {
-		if errors.Is(err, generalerrors.ErrAliasNotFound) {
+		if errors.Is(err, generalerrors.ErrPasswordRequired) || errors.Is(err, generalerrors.ErrWrongPassword) {
+			logger.Info("get url handler", slog.String("error", err.Error()))
+
+			respErr := generalerrors.ErrPasswordRequired
+
+			if errors.Is(err, generalerrors.ErrWrongPassword) {
+				respErr = generalerrors.ErrWrongPassword
+			}
+
+			out, err := newResponseGet(respErr)
+
+			if err != nil {
+				logger.Error("json marshall", slog.String("error", err.Error()))
+
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, generalerrors.ErrAliasNotFound) || errors.Is(err, generalerrors.ErrAliasNotActive) || errors.Is(err, generalerrors.ErrAliasComingSoon) {
 			logger.Debug("get url handler", slog.String("error", err.Error()))
 
 			out, err := newSaveResponse(errors.New("alias not found"))
@@ -129,6 +165,22 @@ func (router *Router) Get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, generalerrors.ErrAliasExpired) || errors.Is(err, generalerrors.ErrClicksExhausted) {
+			logger.Debug("get url handler", slog.String("error", err.Error()))
+
+			out, err := newResponseGet(errors.New("alias is no longer available"))
+
+			if err != nil {
+				logger.Error("json marshall", slog.String("error", err.Error()))
+
+				http.Error(w, "alias is no longer available", http.StatusGone)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusGone)
+			return
+		}
+
 		logger.Error("get url handler error", slog.String("error", err.Error()))
 
 		out, err := newResponseGet(errors.New("internal error"))
@@ -145,7 +197,7 @@ func (router *Router) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := ResponseGet{
-		URL:      url,
+		URL:      url.URL,
 		Response: mainresponse.NewOK(),
 	}
 	responseJSON, err := json.Marshal(resp)
@@ -158,7 +210,10 @@ func (router *Router) Get(w http.ResponseWriter, r *http.Request) {
 
 	logger.Info("success handle request")
 
-	router.popAlias.Inc(req.Alias)
+	if !head {
+		router.popAlias.Inc(req.Alias)
+		router.trackClick(r, url, 0, "")
+	}
 
 	_, err = w.Write(responseJSON)
 
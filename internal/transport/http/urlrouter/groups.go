@@ -0,0 +1,349 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+	validaterequests "github.com/Cwby333/url-shorter/internal/transport/http/lib/validaterequsts"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var errInvalidGroupID = errors.New("id must be a positive number")
+
+type RequestGroup struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+type RequestURLTags struct {
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=64"`
+}
+
+type RequestURLFolder struct {
+	Folder string `json:"folder" validate:"max=64"`
+}
+
+type ResponseGroup struct {
+	Group *urls.Group `json:"group,omitempty"`
+	mainresponse.Response
+}
+
+type ResponseGroups struct {
+	Groups []urls.Group `json:"groups"`
+	mainresponse.Response
+}
+
+type ResponseURLTags struct {
+	Alias string   `json:"alias"`
+	Tags  []string `json:"tags"`
+	mainresponse.Response
+}
+
+type ResponseURLFolder struct {
+	Alias  string `json:"alias"`
+	Folder string `json:"folder"`
+	mainresponse.Response
+}
+
+func newGroupResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/groups.go/newGroupResponse"
+
+	response := ResponseGroup{
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func writeGroupError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
+	out, e := newGroupResponse(err)
+
+	if e != nil {
+		logger.Error("json marshal", slog.String("error", e.Error()))
+
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	http.Error(w, string(out), status)
+}
+
+func writeGroupServiceError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	for _, target := range []error{generalerrors.ErrTagNotFound, generalerrors.ErrFolderNotFound, generalerrors.ErrAliasNotFound} {
+		if errors.Is(err, target) {
+			logger.Debug("group handler", slog.String("error", err.Error()))
+
+			writeGroupError(w, logger, http.StatusNotFound, target)
+			return
+		}
+	}
+
+	for _, target := range []error{generalerrors.ErrTagAlreadyExists, generalerrors.ErrFolderAlreadyExists} {
+		if errors.Is(err, target) {
+			logger.Info("group handler", slog.String("error", err.Error()))
+
+			writeGroupError(w, logger, http.StatusConflict, target)
+			return
+		}
+	}
+
+	if errors.Is(err, generalerrors.ErrNotAliasOwner) {
+		logger.Info("group handler", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusForbidden, generalerrors.ErrNotAliasOwner)
+		return
+	}
+
+	logger.Error("group handler", slog.String("error", err.Error()))
+
+	writeGroupError(w, logger, http.StatusInternalServerError, errors.New(respforusers.ErrInternalError))
+}
+
+func writeGroupResponse(w http.ResponseWriter, logger *slog.Logger, status int, response any) {
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success group handler")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
+
+func (router *Router) decodeGroupRequest(w http.ResponseWriter, r *http.Request, logger *slog.Logger, req any) bool {
+	err := json.NewDecoder(r.Body).Decode(req)
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusBadRequest, errors.New(respforusers.ErrBadRequest))
+		return false
+	}
+
+	r.Body.Close()
+
+	err = router.validator.Struct(req)
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusBadRequest, errors.New(strings.Join(validaterequests.Validate(err.(validator.ValidationErrors)), ", ")))
+		return false
+	}
+
+	return true
+}
+
+func groupHandlerContext(w http.ResponseWriter, r *http.Request, component string) (*slog.Logger, string, bool) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return nil, "", false
+	}
+
+	logger = logger.With("component", component)
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return nil, "", false
+	}
+
+	return logger, sub, true
+}
+
+func groupID(w http.ResponseWriter, r *http.Request, logger *slog.Logger) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+
+	if err != nil || id < 1 {
+		logger.Info("bad request", slog.String("id", r.PathValue("id")))
+
+		writeGroupError(w, logger, http.StatusBadRequest, errInvalidGroupID)
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (router *Router) ListGroups(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger, sub, ok := groupHandlerContext(w, r, "list "+kind+" handler")
+
+		if !ok {
+			return
+		}
+
+		groups, err := router.urlService.ListGroups(r.Context(), kind, sub)
+
+		if err != nil {
+			writeGroupServiceError(w, logger, err)
+			return
+		}
+
+		if groups == nil {
+			groups = []urls.Group{}
+		}
+
+		writeGroupResponse(w, logger, http.StatusOK, ResponseGroups{Groups: groups, Response: mainresponse.NewOK()})
+	}
+}
+
+func (router *Router) CreateGroup(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger, sub, ok := groupHandlerContext(w, r, "create "+kind+" handler")
+
+		if !ok {
+			return
+		}
+
+		req := RequestGroup{}
+
+		if !router.decodeGroupRequest(w, r, logger, &req) {
+			return
+		}
+
+		group, err := router.urlService.CreateGroup(r.Context(), kind, sub, req.Name)
+
+		if err != nil {
+			writeGroupServiceError(w, logger, err)
+			return
+		}
+
+		writeGroupResponse(w, logger, http.StatusCreated, ResponseGroup{Group: &group, Response: mainresponse.NewOK()})
+	}
+}
+
+func (router *Router) RenameGroup(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger, sub, ok := groupHandlerContext(w, r, "rename "+kind+" handler")
+
+		if !ok {
+			return
+		}
+
+		id, ok := groupID(w, r, logger)
+
+		if !ok {
+			return
+		}
+
+		req := RequestGroup{}
+
+		if !router.decodeGroupRequest(w, r, logger, &req) {
+			return
+		}
+
+		group, err := router.urlService.RenameGroup(r.Context(), kind, sub, id, req.Name)
+
+		if err != nil {
+			writeGroupServiceError(w, logger, err)
+			return
+		}
+
+		writeGroupResponse(w, logger, http.StatusOK, ResponseGroup{Group: &group, Response: mainresponse.NewOK()})
+	}
+}
+
+func (router *Router) DeleteGroup(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger, sub, ok := groupHandlerContext(w, r, "delete "+kind+" handler")
+
+		if !ok {
+			return
+		}
+
+		id, ok := groupID(w, r, logger)
+
+		if !ok {
+			return
+		}
+
+		err := router.urlService.DeleteGroup(r.Context(), kind, sub, id)
+
+		if err != nil {
+			writeGroupServiceError(w, logger, err)
+			return
+		}
+
+		writeGroupResponse(w, logger, http.StatusOK, ResponseGroup{Response: mainresponse.NewOK()})
+	}
+}
+
+func (router *Router) SetURLTags(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "set url tags handler")
+
+	if !ok {
+		return
+	}
+
+	req := RequestURLTags{}
+
+	if !router.decodeGroupRequest(w, r, logger, &req) {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	tags, err := router.urlService.SetURLTags(r.Context(), alias, sub, req.Tags)
+
+	if err != nil {
+		writeGroupServiceError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseURLTags{Alias: alias, Tags: tags, Response: mainresponse.NewOK()})
+}
+
+func (router *Router) SetURLFolder(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "set url folder handler")
+
+	if !ok {
+		return
+	}
+
+	req := RequestURLFolder{}
+
+	if !router.decodeGroupRequest(w, r, logger, &req) {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	err := router.urlService.SetURLFolder(r.Context(), alias, sub, req.Folder)
+
+	if err != nil {
+		writeGroupServiceError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseURLFolder{Alias: alias, Folder: strings.TrimSpace(req.Folder), Response: mainresponse.NewOK()})
+}
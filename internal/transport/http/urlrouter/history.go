@@ -0,0 +1,80 @@
+package urlrouter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+)
+
+var errInvalidVersion = errors.New("version must be a positive number")
+
+type ResponseHistory struct {
+	Alias    string         `json:"alias"`
+	Versions []urls.Version `json:"versions"`
+	mainresponse.Response
+}
+
+type ResponseRollback struct {
+	URL *urls.URL `json:"url,omitempty"`
+	mainresponse.Response
+}
+
+func writeHistoryError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	if errors.Is(err, generalerrors.ErrVersionNotFound) {
+		logger.Debug("history handler", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusNotFound, generalerrors.ErrVersionNotFound)
+		return
+	}
+
+	writeDestinationsError(w, logger, err)
+}
+
+func (router *Router) History(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "history handler")
+
+	if !ok {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	versions, err := router.urlService.ListHistory(r.Context(), alias, sub)
+
+	if err != nil {
+		writeHistoryError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseHistory{Alias: alias, Versions: versions, Response: mainresponse.NewOK()})
+}
+
+func (router *Router) Rollback(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "rollback handler")
+
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+
+	if err != nil || version < 1 {
+		logger.Info("bad request", slog.String("version", r.URL.Query().Get("version")))
+
+		writeGroupError(w, logger, http.StatusBadRequest, errInvalidVersion)
+		return
+	}
+
+	url, err := router.urlService.RollbackURL(r.Context(), r.PathValue("alias"), sub, version)
+
+	if err != nil {
+		writeHistoryError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseRollback{URL: &url, Response: mainresponse.NewOK()})
+}
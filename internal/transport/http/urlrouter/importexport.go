@@ -0,0 +1,475 @@
+package urlrouter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+	validaterequests "github.com/Cwby333/url-shorter/internal/transport/http/lib/validaterequsts"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	maxImportBodySize = 64 << 20
+	exportFlushEvery  = 100
+)
+
+var exportCSVHeader = []string{"alias", "url", "redirect_code", "expires_at", "max_clicks", "remaining_clicks", "clicks", "created_at"}
+
+type ImportRow struct {
+	URL          string     `json:"url" validate:"required,url"`
+	Alias        string     `json:"alias" validate:"required,alias"`
+	RedirectCode int        `json:"redirect_code,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	MaxClicks    int        `json:"max_clicks,omitempty" validate:"omitempty,min=1"`
+}
+
+type ResponseImport struct {
+	Result urls.ImportResult `json:"result"`
+	mainresponse.Response
+}
+
+func newImportResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/importexport.go/newImportResponse"
+
+	response := ResponseImport{
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+type importSource struct {
+	next      func() (ImportRow, error)
+	validator *validator.Validate
+	ownerUUID string
+
+	line int
+	url  urls.URL
+	err  error
+}
+
+func (s *importSource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	row, err := s.next()
+
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+
+	s.line++
+
+	if err == nil {
+		err = s.validator.Struct(row)
+
+		if errorsValidation, ok := err.(validator.ValidationErrors); ok {
+			err = errors.New(strings.Join(validaterequests.Validate(errorsValidation), ", "))
+		}
+	}
+
+	if err != nil {
+		s.err = fmt.Errorf("%w: record %d: %s", generalerrors.ErrInvalidImportRow, s.line, err.Error())
+
+		return false
+	}
+
+	var maxClicks *int
+
+	if row.MaxClicks > 0 {
+		maxClicks = &row.MaxClicks
+	}
+
+	s.url = urls.URL{
+		URL:          row.URL,
+		Alias:        row.Alias,
+		RedirectCode: row.RedirectCode,
+		OwnerUUID:    s.ownerUUID,
+		ExpiresAt:    row.ExpiresAt,
+		MaxClicks:    maxClicks,
+	}
+
+	return true
+}
+
+func (s *importSource) URL() urls.URL {
+	return s.url
+}
+
+func (s *importSource) Err() error {
+	return s.err
+}
+
+func ndjsonRows(r io.Reader) func() (ImportRow, error) {
+	decoder := json.NewDecoder(r)
+
+	return func() (ImportRow, error) {
+		row := ImportRow{}
+		err := decoder.Decode(&row)
+
+		return row, err
+	}
+}
+
+func csvRows(r io.Reader) func() (ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var columns map[string]int
+
+	return func() (ImportRow, error) {
+		if columns == nil {
+			header, err := reader.Read()
+
+			if err != nil {
+				return ImportRow{}, err
+			}
+
+			columns = make(map[string]int, len(header))
+
+			for i, name := range header {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+
+			for _, required := range []string{"url", "alias"} {
+				if _, ok := columns[required]; !ok {
+					return ImportRow{}, fmt.Errorf("csv header must contain column %s", required)
+				}
+			}
+		}
+
+		record, err := reader.Read()
+
+		if err != nil {
+			return ImportRow{}, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+
+			if !ok || i >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		row := ImportRow{
+			URL:   field("url"),
+			Alias: field("alias"),
+		}
+
+		if value := field("redirect_code"); value != "" {
+			row.RedirectCode, err = strconv.Atoi(value)
+
+			if err != nil {
+				return ImportRow{}, errors.New("redirect_code must be a number")
+			}
+		}
+
+		if value := field("max_clicks"); value != "" {
+			row.MaxClicks, err = strconv.Atoi(value)
+
+			if err != nil {
+				return ImportRow{}, errors.New("max_clicks must be a number")
+			}
+		}
+
+		if value := field("expires_at"); value != "" {
+			expiresAt, err := time.Parse(time.RFC3339, value)
+
+			if err != nil {
+				return ImportRow{}, errors.New("expires_at must be a RFC3339 timestamp")
+			}
+
+			row.ExpiresAt = &expiresAt
+		}
+
+		return row, nil
+	}
+}
+
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return formatNDJSON
+	}
+
+	return formatCSV
+}
+
+func (router *Router) Import(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "import handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	defer body.Close()
+
+	source := &importSource{
+		validator: router.validator,
+		ownerUUID: sub,
+	}
+
+	switch importFormat(r) {
+	case formatCSV:
+		source.next = csvRows(body)
+	case formatNDJSON:
+		source.next = ndjsonRows(body)
+	default:
+		logger.Info("bad request", slog.String("format", importFormat(r)))
+
+		out, err := newImportResponse(generalerrors.ErrInvalidFormat)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusBadRequest)
+		return
+	}
+
+	result, err := router.urlService.ImportURLs(r.Context(), sub, source, r.URL.Query().Get("on_conflict"))
+
+	if err != nil {
+		var (
+			status  = http.StatusInternalServerError
+			respErr = errors.New(respforusers.ErrInternalError)
+		)
+
+		switch {
+		case errors.Is(err, generalerrors.ErrInvalidImportRow) && source.Err() != nil:
+			status, respErr = http.StatusBadRequest, source.Err()
+		case errors.Is(err, generalerrors.ErrInvalidImportRow) && destinationError(err) != nil:
+			status, respErr = http.StatusBadRequest, fmt.Errorf("%w: record %d: %w", generalerrors.ErrInvalidImportRow, source.line, destinationError(err))
+		case errors.Is(err, generalerrors.ErrInvalidImportPolicy):
+			status, respErr = http.StatusBadRequest, generalerrors.ErrInvalidImportPolicy
+		case errors.Is(err, generalerrors.ErrAliasAlreadyExists):
+			status, respErr = http.StatusConflict, generalerrors.ErrAliasAlreadyExists
+		}
+
+		if status == http.StatusInternalServerError {
+			logger.Error("import handler", slog.String("error", err.Error()))
+		} else {
+			logger.Info("import handler", slog.String("error", err.Error()))
+		}
+
+		out, err := newImportResponse(respErr)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		http.Error(w, string(out), status)
+		return
+	}
+
+	response := ResponseImport{
+		Result:   result,
+		Response: mainresponse.NewOK(),
+	}
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success import handler", slog.Int64("total", result.Total), slog.Int64("inserted", result.Inserted))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
+
+func exportCSVRecord(url urls.URL) []string {
+	record := []string{url.Alias, url.URL, "", "", "", "", strconv.FormatInt(url.Clicks, 10), url.CreatedAt.Format(time.RFC3339)}
+
+	if url.RedirectCode != 0 {
+		record[2] = strconv.Itoa(url.RedirectCode)
+	}
+	if url.ExpiresAt != nil {
+		record[3] = url.ExpiresAt.Format(time.RFC3339)
+	}
+	if url.MaxClicks != nil {
+		record[4] = strconv.Itoa(*url.MaxClicks)
+	}
+	if url.RemainingClicks != nil {
+		record[5] = strconv.Itoa(*url.RemainingClicks)
+	}
+
+	return record
+}
+
+func (router *Router) Export(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "export handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+
+	if format == "" {
+		format = formatCSV
+	}
+
+	var write func(url urls.URL) error
+	var flush func() error
+
+	switch format {
+	case formatCSV:
+		writer := csv.NewWriter(w)
+
+		write = func(url urls.URL) error {
+			return writer.Write(exportCSVRecord(url))
+		}
+		flush = func() error {
+			writer.Flush()
+
+			return writer.Error()
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="urls.csv"`)
+
+		err = writer.Write(exportCSVHeader)
+
+		if err != nil {
+			logger.Error("csv writer", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+			return
+		}
+	case formatNDJSON:
+		encoder := json.NewEncoder(w)
+
+		write = func(url urls.URL) error {
+			return encoder.Encode(url)
+		}
+		flush = func() error {
+			return nil
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="urls.ndjson"`)
+	default:
+		logger.Info("bad request", slog.String("format", format))
+
+		http.Error(w, generalerrors.ErrInvalidFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	count := 0
+
+	err = router.urlService.ExportURLs(r.Context(), sub, func(url urls.URL) error {
+		err := write(url)
+
+		if err != nil {
+			return err
+		}
+
+		count++
+
+		if count%exportFlushEvery != 0 {
+			return nil
+		}
+
+		err = flush()
+
+		if err != nil {
+			return err
+		}
+
+		return controller.Flush()
+	})
+
+	if err == nil {
+		err = flush()
+	}
+
+	if err != nil {
+		if count == 0 {
+			logger.Error("export handler", slog.String("error", err.Error()))
+
+			w.Header().Del("Content-Disposition")
+			http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+			return
+		}
+
+		logger.Error("export handler, response truncated", slog.String("error", err.Error()), slog.Int("written", count))
+		return
+	}
+
+	logger.Info("success export handler", slog.Int("count", count))
+}
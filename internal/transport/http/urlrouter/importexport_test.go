@@ -0,0 +1,183 @@
+package urlrouter
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/aliaspolicy"
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/go-playground/validator/v10"
+)
+
+func newImportValidator(t *testing.T) *validator.Validate {
+	t.Helper()
+
+	policy, err := aliaspolicy.New(config.AliasPolicy{
+		Charset:   "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_",
+		MinLength: 3,
+		MaxLength: 32,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	err = policy.Register(validate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return validate
+}
+
+func readImport(t *testing.T, source *importSource) []urls.URL {
+	t.Helper()
+
+	out := make([]urls.URL, 0)
+
+	for source.Next() {
+		out = append(out, source.URL())
+	}
+
+	return out
+}
+
+func TestImportSourceRows(t *testing.T) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	maxClicks := 5
+
+	want := []urls.URL{
+		{URL: "https://example.com/a", Alias: "first", OwnerUUID: "owner"},
+		{URL: "https://example.com/b", Alias: "second", RedirectCode: 301, OwnerUUID: "owner", ExpiresAt: &expires, MaxClicks: &maxClicks},
+	}
+
+	tests := []struct {
+		name string
+		next func() (ImportRow, error)
+	}{
+		{
+			name: "csv",
+			next: csvRows(strings.NewReader("Alias, URL ,redirect_code,expires_at,max_clicks,extra\n" +
+				"first,https://example.com/a,,,,x\n" +
+				"second, https://example.com/b ,301,2030-01-02T03:04:05Z,5\n")),
+		},
+		{
+			name: "ndjson",
+			next: ndjsonRows(strings.NewReader(`{"url":"https://example.com/a","alias":"first"}` + "\n" +
+				`{"url":"https://example.com/b","alias":"second","redirect_code":301,"expires_at":"2030-01-02T03:04:05Z","max_clicks":5}` + "\n")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &importSource{next: tt.next, validator: newImportValidator(t), ownerUUID: "owner"}
+			got := readImport(t, source)
+
+			if source.Err() != nil {
+				t.Fatalf("Err() = %v", source.Err())
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("read %d rows, want %d", len(got), len(want))
+			}
+
+			for i := range want {
+				if !sameImportURL(got[i], want[i]) {
+					t.Errorf("row %d = %+v, want %+v", i+1, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImportSourceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		json string
+		line int
+		want string
+	}{
+		{name: "csv missing column", csv: "alias\nfirst\n", line: 1, want: "csv header must contain column url"},
+		{name: "csv bad redirect code", csv: "alias,url,redirect_code\nfirst,https://example.com,abc\n", line: 1, want: "redirect_code must be a number"},
+		{name: "csv bad max clicks", csv: "alias,url,max_clicks\nfirst,https://example.com,x\n", line: 1, want: "max_clicks must be a number"},
+		{name: "csv bad expires at", csv: "alias,url,expires_at\nfirst,https://example.com,tomorrow\n", line: 1, want: "expires_at must be a RFC3339 timestamp"},
+		{name: "csv invalid url on second row", csv: "alias,url\nfirst,https://example.com\nsecond,not a url\n", line: 2, want: "record 2: invalid url"},
+		{name: "csv reserved alias", csv: "alias,url\nadmin,https://example.com\n", line: 1, want: "field Alias is a reserved word"},
+		{name: "csv bad redirect value", csv: "alias,url,redirect_code\nfirst,https://example.com,200\n", line: 1, want: "field RedirectCode must be one of"},
+		{name: "ndjson missing alias", json: `{"url":"https://example.com"}`, line: 1, want: "field Alias"},
+		{name: "ndjson malformed", json: `{"url":"https://example.com","alias":"first"}` + "\n{", line: 2, want: "unexpected EOF"},
+		{name: "ndjson negative max clicks", json: `{"url":"https://example.com","alias":"first","max_clicks":-1}`, line: 1, want: "field MaxClicks must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := csvRows(strings.NewReader(tt.csv))
+
+			if tt.json != "" {
+				next = ndjsonRows(strings.NewReader(tt.json))
+			}
+
+			source := &importSource{next: next, validator: newImportValidator(t), ownerUUID: "owner"}
+			readImport(t, source)
+
+			err := source.Err()
+
+			if !errors.Is(err, generalerrors.ErrInvalidImportRow) {
+				t.Fatalf("Err() = %v, want %v", err, generalerrors.ErrInvalidImportRow)
+			}
+
+			if source.line != tt.line {
+				t.Errorf("failed on record %d, want %d", source.line, tt.line)
+			}
+
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Err() = %q, want it to mention %q", err, tt.want)
+			}
+
+			if source.Next() {
+				t.Error("Next() = true after an error")
+			}
+		})
+	}
+}
+
+func TestImportFormat(t *testing.T) {
+	tests := []struct {
+		target      string
+		contentType string
+		want        string
+	}{
+		{target: "/import", want: formatCSV},
+		{target: "/import", contentType: "text/csv", want: formatCSV},
+		{target: "/import", contentType: "application/x-ndjson; charset=utf-8", want: formatNDJSON},
+		{target: "/import", contentType: "application/jsonl", want: formatNDJSON},
+		{target: "/import?format=ndjson", contentType: "text/csv", want: formatNDJSON},
+		{target: "/import?format=xml", want: "xml"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", tt.target, nil)
+
+		if tt.contentType != "" {
+			r.Header.Set("Content-Type", tt.contentType)
+		}
+
+		if got := importFormat(r); got != tt.want {
+			t.Errorf("importFormat(%s, %q) = %q, want %q", tt.target, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func sameImportURL(a, b urls.URL) bool {
+	sameTime := a.ExpiresAt == nil && b.ExpiresAt == nil || a.ExpiresAt != nil && b.ExpiresAt != nil && a.ExpiresAt.Equal(*b.ExpiresAt)
+	sameClicks := a.MaxClicks == nil && b.MaxClicks == nil || a.MaxClicks != nil && b.MaxClicks != nil && *a.MaxClicks == *b.MaxClicks
+
+	return a.URL == b.URL && a.Alias == b.Alias && a.RedirectCode == b.RedirectCode && a.OwnerUUID == b.OwnerUUID && sameTime && sameClicks
+}
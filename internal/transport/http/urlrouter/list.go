@@ -0,0 +1,173 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+type ResponseList struct {
+	URLs       []urls.URL `json:"urls"`
+	NextCursor string     `json:"next_cursor"`
+	mainresponse.Response
+}
+
+func newListResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/list.go/newListResponse"
+
+	response := ResponseList{
+		URLs:     []urls.URL{},
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (router *Router) List(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "list handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter := urls.ListFilter{
+		OwnerUUID: sub,
+		Query:     query.Get("q"),
+		Tag:       query.Get("tag"),
+		Folder:    query.Get("folder"),
+		SortBy:    query.Get("sort"),
+		Limit:     defaultListLimit,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+
+		if err != nil || filter.Limit < 1 || filter.Limit > maxListLimit {
+			logger.Info("bad request", slog.String("limit", limit))
+
+			out, err := newListResponse(fmt.Errorf("limit must be between 1 and %d", maxListLimit))
+
+			if err != nil {
+				logger.Error("json marshal", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if filter.SortBy != "" && filter.SortBy != urls.SortByCreated && filter.SortBy != urls.SortByClicks {
+		logger.Info("bad request", slog.String("sort", filter.SortBy))
+
+		out, err := newListResponse(fmt.Errorf("sort must be one of: %s %s", urls.SortByCreated, urls.SortByClicks))
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusBadRequest)
+		return
+	}
+
+	list, nextCursor, err := router.urlService.ListURLs(r.Context(), filter, query.Get("cursor"))
+
+	if err != nil {
+		if errors.Is(err, generalerrors.ErrInvalidCursor) {
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			out, err := newListResponse(generalerrors.ErrInvalidCursor)
+
+			if err != nil {
+				logger.Error("json marshal", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusBadRequest)
+			return
+		}
+
+		logger.Error("list handler", slog.String("error", err.Error()))
+
+		out, err := newListResponse(errors.New(respforusers.ErrInternalError))
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusInternalServerError)
+		return
+	}
+
+	if list == nil {
+		list = []urls.URL{}
+	}
+
+	response := ResponseList{
+		URLs:       list,
+		NextCursor: nextCursor,
+		Response:   mainresponse.NewOK(),
+	}
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success list handler")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
@@ -14,29 +14,39 @@ func (r Router) StartProcessPopAlias() {
 	ticker := time.NewTicker(r.popAlias.TimeSend)
 
 	go func() {
+		defer ticker.Stop()
+
 		for {
 			select {
 			case <-r.mainCtx.Done():
+				ctx, cancel := context.WithTimeout(context.Background(), defaultTimeoutForSendPop)
+				r.sendPopAliases(ctx)
+				cancel()
+
 				return
 			case <-ticker.C:
-				popAlias, countOfReq := r.popAlias.GetMostPopularAlias()
+				ctx, cancel := context.WithTimeout(r.mainCtx, defaultTimeoutForSendPop)
+				r.sendPopAliases(ctx)
+				cancel()
+			}
+		}
+	}()
+}
 
-				if popAlias == "" {
-					r.logger.Info("zero get urls requests in duration", slog.Any("duration", r.popAlias.TimeSend.Seconds()))
-					continue
-				}
+func (r Router) sendPopAliases(ctx context.Context) {
+	aliases := r.popAlias.Flush()
 
-				ctx, cancel := context.WithTimeout(r.mainCtx, defaultTimeSendPopAlias)
-				defer cancel()
-				err := r.urlService.SendPopAlias(ctx, popAlias, countOfReq)
+	if len(aliases) == 0 {
+		r.logger.Debug("zero get urls requests in duration", slog.Any("duration", r.popAlias.TimeSend.Seconds()))
+		return
+	}
 
-				if err != nil {
-					r.logger.Error("send pop alias", slog.String("error", err.Error()))
-					continue
-				}
+	err := r.urlService.SavePopularAliases(ctx, aliases)
 
-				r.logger.Info("success send pop alias")
-			}
-		}
-	}()
+	if err != nil {
+		r.logger.Error("send pop aliases", slog.String("error", err.Error()), slog.Int("lost", len(aliases)))
+		return
+	}
+
+	r.logger.Debug("success send pop aliases", slog.Int("count", len(aliases)))
 }
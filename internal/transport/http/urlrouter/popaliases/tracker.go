@@ -0,0 +1,119 @@
+package popaliases
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+)
+
+type counter struct {
+	alias string
+	count int64
+	err   int64
+	index int
+}
+
+type minHeap []*counter
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h minHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *minHeap) Push(x any) {
+	c := x.(*counter)
+	c.index = len(*h)
+	*h = append(*h, c)
+}
+
+func (h *minHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return c
+}
+
+// Tracker counts alias hits with the Space-Saving algorithm: memory is bounded
+// by capacity and every alias hit more than total/capacity times is kept.
+type Tracker struct {
+	mu       *sync.Mutex
+	capacity int
+	counters map[string]*counter
+	heap     *minHeap
+	TimeSend time.Duration
+}
+
+func New(capacity int, timeSend time.Duration) Tracker {
+	return Tracker{
+		mu:       &sync.Mutex{},
+		capacity: capacity,
+		counters: make(map[string]*counter, capacity),
+		heap:     &minHeap{},
+		TimeSend: timeSend,
+	}
+}
+
+func (t Tracker) Inc(alias string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if c, ok := t.counters[alias]; ok {
+		c.count++
+		heap.Fix(t.heap, c.index)
+		return
+	}
+
+	if len(t.counters) < t.capacity {
+		c := &counter{alias: alias, count: 1}
+		heap.Push(t.heap, c)
+		t.counters[alias] = c
+		return
+	}
+
+	smallest := (*t.heap)[0]
+	delete(t.counters, smallest.alias)
+
+	smallest.alias = alias
+	smallest.err = smallest.count
+	smallest.count++
+	t.counters[alias] = smallest
+
+	heap.Fix(t.heap, smallest.index)
+}
+
+// Flush returns guaranteed counts (estimate minus error), highest first, and resets the tracker.
+func (t Tracker) Flush() []urls.PopularAlias {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]urls.PopularAlias, 0, len(t.counters))
+
+	for _, c := range t.counters {
+		if guaranteed := c.count - c.err; guaranteed > 0 {
+			out = append(out, urls.PopularAlias{
+				Alias: c.alias,
+				Count: guaranteed,
+			})
+		}
+	}
+
+	clear(t.counters)
+	clear(*t.heap)
+	*t.heap = (*t.heap)[:0]
+
+	slices.SortFunc(out, func(a, b urls.PopularAlias) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	return out
+}
@@ -0,0 +1,127 @@
+package popaliases
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+)
+
+func TestTrackerFlush(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		hits     []string
+		want     []urls.PopularAlias
+	}{
+		{
+			name:     "empty",
+			capacity: 2,
+			want:     []urls.PopularAlias{},
+		},
+		{
+			name:     "exact under capacity",
+			capacity: 3,
+			hits:     []string{"a", "b", "a", "c", "a", "b"},
+			want:     []urls.PopularAlias{{Alias: "a", Count: 3}, {Alias: "b", Count: 2}, {Alias: "c", Count: 1}},
+		},
+		{
+			name:     "evicts the smallest and keeps its count as error",
+			capacity: 2,
+			hits:     []string{"a", "a", "a", "b", "c"},
+			want:     []urls.PopularAlias{{Alias: "a", Count: 3}, {Alias: "c", Count: 1}},
+		},
+		{
+			name:     "reports only hits seen since the eviction",
+			capacity: 1,
+			hits:     []string{"a", "a", "b", "b"},
+			want:     []urls.PopularAlias{{Alias: "b", Count: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := New(tt.capacity, time.Minute)
+
+			for _, alias := range tt.hits {
+				tracker.Inc(alias)
+			}
+
+			if got := tracker.Flush(); !slices.Equal(got, tt.want) {
+				t.Errorf("Flush() = %v, want %v", got, tt.want)
+			}
+
+			if got := tracker.Flush(); len(got) != 0 {
+				t.Errorf("second Flush() = %v, want empty", got)
+			}
+		})
+	}
+}
+
+func TestTrackerHeavyHitter(t *testing.T) {
+	const capacity = 10
+
+	tracker := New(capacity, time.Minute)
+	truth := map[string]int64{}
+
+	for i := range 2000 {
+		alias := "tail-" + strconv.Itoa(i)
+
+		if i%2 == 0 {
+			alias = "hot"
+		}
+
+		tracker.Inc(alias)
+		truth[alias]++
+	}
+
+	got := tracker.Flush()
+
+	if len(got) == 0 || got[0].Alias != "hot" {
+		t.Fatalf("Flush() = %v, want hot first", got)
+	}
+
+	if len(got) > capacity {
+		t.Errorf("Flush() returned %d aliases, capacity is %d", len(got), capacity)
+	}
+
+	for _, alias := range got {
+		if alias.Count > truth[alias.Alias] {
+			t.Errorf("%s: guaranteed count %d exceeds true count %d", alias.Alias, alias.Count, truth[alias.Alias])
+		}
+	}
+
+	// Space-Saving overestimates by at most total/capacity, so the guaranteed count stays above that.
+	if floor := truth["hot"] - 2000/capacity; got[0].Count < floor {
+		t.Errorf("hot: guaranteed count %d, want at least %d", got[0].Count, floor)
+	}
+}
+
+func TestTrackerConcurrentInc(t *testing.T) {
+	tracker := New(4, time.Minute)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 1000 {
+				tracker.Inc("a")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	want := []urls.PopularAlias{{Alias: "a", Count: 8000}}
+
+	if got := tracker.Flush(); !slices.Equal(got, want) {
+		t.Errorf("Flush() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+type ResponsePopular struct {
+	Window  string              `json:"window,omitempty"`
+	Aliases []urls.PopularAlias `json:"aliases"`
+	mainresponse.Response
+}
+
+func newPopularResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/popular.go/newPopularResponse"
+
+	response := ResponsePopular{
+		Aliases:  []urls.PopularAlias{},
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func (router *Router) Popular(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "popular handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+
+	window := query.Get("window")
+
+	if window == "" {
+		window = urls.WindowDay
+	}
+
+	limit := defaultListLimit
+
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+
+		if err != nil || limit < 1 || limit > maxListLimit {
+			logger.Info("bad request", slog.String("limit", value))
+
+			out, err := newPopularResponse(fmt.Errorf("limit must be between 1 and %d", maxListLimit))
+
+			if err != nil {
+				logger.Error("json marshal", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusBadRequest)
+			return
+		}
+	}
+
+	aliases, err := router.urlService.PopularAliases(r.Context(), sub, window, limit)
+
+	if err != nil {
+		if errors.Is(err, generalerrors.ErrInvalidWindow) {
+			logger.Info("bad request", slog.String("window", window))
+
+			out, err := newPopularResponse(generalerrors.ErrInvalidWindow)
+
+			if err != nil {
+				logger.Error("json marshal", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+				return
+			}
+
+			http.Error(w, string(out), http.StatusBadRequest)
+			return
+		}
+
+		logger.Error("popular handler", slog.String("error", err.Error()))
+
+		out, err := newPopularResponse(errors.New(respforusers.ErrInternalError))
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusInternalServerError)
+		return
+	}
+
+	if aliases == nil {
+		aliases = []urls.PopularAlias{}
+	}
+
+	response := ResponsePopular{
+		Window:   window,
+		Aliases:  aliases,
+		Response: mainresponse.NewOK(),
+	}
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success popular handler")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
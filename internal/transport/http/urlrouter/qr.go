@@ -0,0 +1,111 @@
+package urlrouter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/services/qrservice"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+// publicURL never falls back to the Host or X-Forwarded-* headers: both are
+// client controlled, and the generated image is cached by its content.
+func (router *Router) publicURL(alias string) (string, error) {
+	if router.baseURL == "" {
+		return "", generalerrors.ErrBaseURLRequired
+	}
+
+	return router.baseURL + "/" + url.PathEscape(alias), nil
+}
+
+func (router *Router) QR(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "qr handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	query := r.URL.Query()
+
+	content, err := router.publicURL(alias)
+
+	if err != nil {
+		logger.Error("qr handler", slog.String("error", err.Error()))
+
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	opts := qrservice.Options{
+		Format: query.Get("format"),
+		Level:  query.Get("ecc"),
+	}
+
+	if size := query.Get("size"); size != "" {
+		opts.Size, err = strconv.Atoi(size)
+
+		if err != nil {
+			logger.Info("bad request", slog.String("size", size))
+
+			http.Error(w, generalerrors.ErrInvalidQROptions.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts = opts.WithDefaults()
+
+	data, err := router.qrService.Generate(r.Context(), alias, sub, content, opts)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, generalerrors.ErrInvalidQROptions):
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			http.Error(w, generalerrors.ErrInvalidQROptions.Error(), http.StatusBadRequest)
+		case errors.Is(err, generalerrors.ErrAliasNotFound):
+			logger.Debug("qr handler", slog.String("error", err.Error()))
+
+			http.Error(w, generalerrors.ErrAliasNotFound.Error(), http.StatusNotFound)
+		case errors.Is(err, generalerrors.ErrNotAliasOwner):
+			logger.Info("qr handler", slog.String("error", err.Error()))
+
+			http.Error(w, generalerrors.ErrNotAliasOwner.Error(), http.StatusForbidden)
+		default:
+			logger.Error("qr handler", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	logger.Info("success qr handler")
+
+	w.Header().Set("Content-Type", opts.ContentType())
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,194 @@
+package urlrouter
+
+import (
+	"cmp"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/pages"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+const (
+	maxPasswordFormSize = 4096
+
+	variantCookiePrefix = "dst_"
+	variantCookieMaxAge = 30 * 24 * 60 * 60
+)
+
+func (router *Router) Redirect(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "redirect handler")
+
+	alias := r.PathValue("alias")
+
+	lookup := router.urlService.GetURL
+
+	if r.Method == http.MethodHead {
+		lookup = router.urlService.PeekURL
+	}
+
+	url, err := lookup(r.Context(), alias)
+
+	if err != nil {
+		router.writeResolveError(w, logger, err)
+		return
+	}
+
+	router.writeRedirect(w, r, logger, url)
+}
+
+func (router *Router) Unlock(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "unlock handler")
+
+	alias := r.PathValue("alias")
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPasswordFormSize)
+	password := r.PostFormValue("password")
+
+	url, err := router.urlService.GetProtectedURL(r.Context(), alias, password)
+
+	if err != nil {
+		router.writeResolveError(w, logger, err)
+		return
+	}
+
+	router.writeRedirect(w, r, logger, url)
+}
+
+func (router *Router) writeRedirect(w http.ResponseWriter, r *http.Request, logger *slog.Logger, url urls.URL) {
+	code := url.RedirectCode
+
+	if code == 0 {
+		code = router.redirectCode
+	}
+
+	if r.Method == http.MethodPost {
+		code = http.StatusSeeOther
+	}
+
+	target, variant := url.URL, 0
+	destination := ""
+
+	rule, matched := urls.Rule{}, false
+
+	if len(url.Rules) > 0 {
+		rule, matched = url.MatchRule(router.visitor(r))
+	}
+
+	if matched {
+		target = rule.URL
+		destination = target
+	} else if len(url.Destinations) > 1 {
+		variant = router.pickVariant(w, r, url)
+		target = url.Destinations[variant].URL
+		destination = target
+	}
+
+	if r.Method != http.MethodHead {
+		router.popAlias.Inc(url.Alias)
+		router.trackClick(r, url, variant, destination)
+	}
+
+	logger.Info("success redirect", slog.String("alias", url.Alias), slog.Int("code", code), slog.Int("variant", variant))
+
+	http.Redirect(w, r, target, code)
+}
+
+// pickVariant keeps sticky visitors on the destination stored in their cookie and re-rolls once it is gone.
+func (router *Router) pickVariant(w http.ResponseWriter, r *http.Request, url urls.URL) int {
+	name := variantCookiePrefix + url.Alias
+
+	if url.Sticky {
+		if cookie, err := r.Cookie(name); err == nil {
+			if variant, ok := url.VariantByKey(cookie.Value); ok {
+				return variant
+			}
+		}
+	}
+
+	variant := url.Variant(rand.IntN(url.TotalWeight()))
+
+	if url.Sticky {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    url.Destinations[variant].Key(),
+			Path:     "/" + url.Alias,
+			MaxAge:   variantCookieMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	return variant
+}
+
+func (router *Router) writeResolveError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, generalerrors.ErrAliasNotFound), errors.Is(err, generalerrors.ErrAliasNotActive):
+		logger.Debug("resolve alias", slog.String("error", err.Error()))
+
+		err = pages.Write(w, http.StatusNotFound, pages.NotFound)
+	case errors.Is(err, generalerrors.ErrAliasComingSoon):
+		logger.Debug("resolve alias", slog.String("error", err.Error()))
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		err = pages.Write(w, http.StatusOK, pages.ComingSoon)
+	case errors.Is(err, generalerrors.ErrAliasExpired), errors.Is(err, generalerrors.ErrClicksExhausted):
+		logger.Debug("resolve alias", slog.String("error", err.Error()))
+
+		err = pages.Write(w, http.StatusGone, pages.Gone)
+	case errors.Is(err, generalerrors.ErrPasswordRequired):
+		logger.Debug("resolve alias", slog.String("error", err.Error()))
+
+		err = pages.WritePasswordForm(w, http.StatusOK, "")
+	case errors.Is(err, generalerrors.ErrWrongPassword):
+		logger.Info("resolve alias", slog.String("error", err.Error()))
+
+		err = pages.WritePasswordForm(w, http.StatusUnauthorized, "Wrong password, please try again.")
+	default:
+		logger.Error("resolve alias", slog.String("error", err.Error()))
+
+		err = pages.Write(w, http.StatusInternalServerError, pages.InternalError)
+	}
+
+	if err != nil {
+		logger.Error("write page", slog.String("error", err.Error()))
+	}
+}
+
+func (router *Router) trackClick(r *http.Request, url urls.URL, variant int, destination string) {
+	router.urlService.TrackClick(clicks.Click{
+		URLID:       url.ID,
+		Alias:       url.Alias,
+		ClickedAt:   time.Now(),
+		Referrer:    r.Referer(),
+		UserAgent:   r.UserAgent(),
+		IP:          r.RemoteAddr,
+		RequestID:   r.Header.Get("X-REQUEST-ID"),
+		Variant:     variant,
+		Destination: destination,
+	}, cmp.Or(destination, url.URL))
+}
@@ -2,13 +2,20 @@ package urlrouter
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/Cwby333/url-shorter/internal/aliaspolicy"
+	"github.com/Cwby333/url-shorter/internal/config"
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/logger"
+	"github.com/Cwby333/url-shorter/internal/services/qrservice"
 	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/jwtmiddle"
 	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/limitermidde"
 	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/logging"
@@ -21,32 +28,74 @@ import (
 
 const (
 	defaultTimeSendPopAlias = time.Duration(time.Second * 20)
+	defaultPopAliasCapacity = 10000
 )
 
 type URLService interface {
-	SaveAlias(ctx context.Context, url, alias string) (int, error)
-	GetURL(ctx context.Context, alias string) (string, error)
-	DeleteURL(ctx context.Context, alias string) error
-	UpdateURL(ctx context.Context, alias, newURL string) error
-	SendPopAlias(ctx context.Context, alias string, countOfReq int) error
+	SaveAlias(ctx context.Context, url urls.URL, password string) (urls.URL, error)
+	SaveAliases(ctx context.Context, batch []urls.BatchItem) ([]urls.SaveResult, error)
+	GetURL(ctx context.Context, alias string) (urls.URL, error)
+	GetProtectedURL(ctx context.Context, alias, password string) (urls.URL, error)
+	PeekURL(ctx context.Context, alias string) (urls.URL, error)
+	PeekProtectedURL(ctx context.Context, alias, password string) (urls.URL, error)
+	DeleteURL(ctx context.Context, alias, ownerUUID string) error
+	UpdateURL(ctx context.Context, newURL, alias, ownerUUID string, activation *urls.Activation) (urls.URL, error)
+	SavePopularAliases(ctx context.Context, aliases []urls.PopularAlias) error
+	PopularAliases(ctx context.Context, ownerUUID, window string, limit int) ([]urls.PopularAlias, error)
+	TrackClick(click clicks.Click, target string)
+	ListURLs(ctx context.Context, filter urls.ListFilter, cursor string) ([]urls.URL, string, error)
+	SearchURLs(ctx context.Context, ownerUUID, query string, limit int, cursor string) ([]urls.URL, string, error)
+	ImportURLs(ctx context.Context, ownerUUID string, source urls.ImportSource, policy string) (urls.ImportResult, error)
+	ExportURLs(ctx context.Context, ownerUUID string, fn func(url urls.URL) error) error
+	CreateGroup(ctx context.Context, kind, ownerUUID, name string) (urls.Group, error)
+	ListGroups(ctx context.Context, kind, ownerUUID string) ([]urls.Group, error)
+	RenameGroup(ctx context.Context, kind, ownerUUID string, id int64, name string) (urls.Group, error)
+	DeleteGroup(ctx context.Context, kind, ownerUUID string, id int64) error
+	SetURLTags(ctx context.Context, alias, ownerUUID string, tags []string) ([]string, error)
+	SetURLFolder(ctx context.Context, alias, ownerUUID, folder string) error
+	SetDestinations(ctx context.Context, alias, ownerUUID string, destinations []urls.Destination, sticky bool) error
+	GetRules(ctx context.Context, alias, ownerUUID string) (urls.URL, error)
+	SetRules(ctx context.Context, alias, ownerUUID string, rules []urls.Rule) ([]urls.Rule, error)
+	ListHistory(ctx context.Context, alias, ownerUUID string) ([]urls.Version, error)
+	RollbackURL(ctx context.Context, alias, ownerUUID string, version int) (urls.URL, error)
+	ListTrash(ctx context.Context, ownerUUID string) ([]urls.URL, error)
+	RestoreURL(ctx context.Context, alias, ownerUUID string) (urls.URL, error)
+	SetActivation(ctx context.Context, alias, ownerUUID string, activeFrom *time.Time, comingSoon bool) (urls.URL, error)
 }
 
-type Router struct {
-	mainCtx context.Context
+type StatsService interface {
+	GetStats(ctx context.Context, alias, ownerUUID string, from, to time.Time, interval string) (clicks.Stats, error)
+}
 
-	mu         *sync.RWMutex
-	urlService URLService
-	limiter    ratelimiter.Limiter
-	logger     logger.Logger
-	Router     *http.ServeMux
-	validator  *validator.Validate
+type GeoLocator interface {
+	Country(addr string) string
+}
 
-	sliceForRandAlias []rune
+type QRService interface {
+	Generate(ctx context.Context, alias, ownerUUID, content string, opts qrservice.Options) ([]byte, error)
+}
+
+type Router struct {
+	mainCtx context.Context
 
-	popAlias popaliases.PopAlias
+	mu           *sync.RWMutex
+	urlService   URLService
+	statsService StatsService
+	qrService    QRService
+	limiter      ratelimiter.Limiter
+	logger       logger.Logger
+	Router       *http.ServeMux
+	validator    *validator.Validate
+	aliasPolicy  *aliaspolicy.Policy
+	geo          GeoLocator
+
+	redirectCode int
+	baseURL      string
+
+	popAlias popaliases.Tracker
 }
 
-func New(service URLService, logger logger.Logger, limiter ratelimiter.Limiter, mainCtx context.Context) (*Router, error) {
+func New(service URLService, statsService StatsService, qrService QRService, aliasPolicy *aliaspolicy.Policy, geo GeoLocator, logger logger.Logger, limiter ratelimiter.Limiter, redirectCfg config.Redirect, mainCtx context.Context) (*Router, error) {
 	const op = "internal/transport/httptransport/urlrouter/New"
 
 	if service == (URLService)(nil) {
@@ -54,30 +103,128 @@ func New(service URLService, logger logger.Logger, limiter ratelimiter.Limiter,
 
 		return nil, generalerrors.ErrNilPointerInInterface
 	}
+	if statsService == (StatsService)(nil) {
+		logger.Error("nil pointer in StatsService interface", slog.String("op", op))
+
+		return nil, generalerrors.ErrNilPointerInInterface
+	}
+	if qrService == (QRService)(nil) {
+		logger.Error("nil pointer in QRService interface", slog.String("op", op))
+
+		return nil, generalerrors.ErrNilPointerInInterface
+	}
+	if aliasPolicy == nil {
+		logger.Error("nil pointer to alias policy", slog.String("op", op))
 
-	data := []rune("QWERTYUIOPASDFGHJKLZXCVBNMqwertyuiopasdfghjklzxcvbnm1234567890")
+		return nil, generalerrors.ErrNilPointerInInterface
+	}
+	if geo == (GeoLocator)(nil) {
+		logger.Error("nil pointer in GeoLocator interface", slog.String("op", op))
+
+		return nil, generalerrors.ErrNilPointerInInterface
+	}
+
+	switch redirectCfg.StatusCode {
+	case 0:
+		redirectCfg.StatusCode = http.StatusFound
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, fmt.Errorf("%s: %w", op, generalerrors.ErrInvalidRedirectCode)
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	err := aliasPolicy.Register(validate)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if redirectCfg.BaseURL == "" {
+		logger.Warn("redirect base-url is not set, qr codes are disabled")
+	}
 
 	return &Router{
-		mainCtx:           mainCtx,
-		mu:                &sync.RWMutex{},
-		urlService:        service,
-		limiter:           limiter,
-		logger:            logger,
-		sliceForRandAlias: data,
-		validator:         validator.New(validator.WithRequiredStructEnabled()),
-		Router:            http.NewServeMux(),
-		popAlias:          popaliases.New(defaultTimeSendPopAlias),
+		mainCtx:      mainCtx,
+		mu:           &sync.RWMutex{},
+		urlService:   service,
+		statsService: statsService,
+		qrService:    qrService,
+		limiter:      limiter,
+		logger:       logger,
+		redirectCode: redirectCfg.StatusCode,
+		baseURL:      strings.TrimRight(redirectCfg.BaseURL, "/"),
+		validator:    validate,
+		aliasPolicy:  aliasPolicy,
+		geo:          geo,
+		Router:       http.NewServeMux(),
+		popAlias:     popaliases.New(defaultPopAliasCapacity, defaultTimeSendPopAlias),
 	}, nil
 }
 
+func (router *Router) handle(pattern string, handler http.Handler) {
+	router.Router.Handle(pattern, handler)
+	router.aliasPolicy.ReservePattern(pattern)
+}
+
 func (router *Router) Run() {
-	router.Router.Handle("POST /create", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Save)))))))
+	router.handle("POST /create", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Save)))))))
+
+	router.handle("POST /batch", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Batch)))))))
+
+	router.handle("POST /import", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Import)))))))
+
+	router.handle("GET /export", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Export)))))))
+
+	router.handle("GET /get", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(limitermidde.New(router.limiter)(http.HandlerFunc(router.Get))))))
+
+	router.handle("DELETE /delete", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Delete)))))))
+
+	router.handle("PUT /update", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.UpdateURL)))))))
+
+	router.handle("GET /{$}", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.List)))))))
+
+	router.handle("GET /popular", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Popular)))))))
+
+	router.handle("GET /search", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Search)))))))
+
+	router.handle("GET /{alias}/stats", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Stats)))))))
+
+	router.handle("GET /{alias}/qr", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.QR)))))))
+
+	router.handle("GET /tags", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.ListGroups(urls.GroupTags)))))))
+
+	router.handle("POST /tags", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.CreateGroup(urls.GroupTags)))))))
+
+	router.handle("PATCH /tags/{id}", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.RenameGroup(urls.GroupTags)))))))
+
+	router.handle("DELETE /tags/{id}", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.DeleteGroup(urls.GroupTags)))))))
+
+	router.handle("GET /folders", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.ListGroups(urls.GroupFolders)))))))
+
+	router.handle("POST /folders", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.CreateGroup(urls.GroupFolders)))))))
+
+	router.handle("PATCH /folders/{id}", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.RenameGroup(urls.GroupFolders)))))))
+
+	router.handle("DELETE /folders/{id}", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(router.DeleteGroup(urls.GroupFolders)))))))
+
+	router.handle("PUT /{alias}/tags", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.SetURLTags)))))))
+
+	router.handle("PUT /{alias}/folder", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.SetURLFolder)))))))
+
+	router.handle("PUT /{alias}/destinations", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.SetDestinations)))))))
+
+	router.handle("GET /{alias}/rules", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.GetRules)))))))
+
+	router.handle("PUT /{alias}/rules", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.SetRules)))))))
+
+	router.handle("GET /{alias}/history", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.History)))))))
 
-	router.Router.Handle("GET /get", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(limitermidde.New(router.limiter)(http.HandlerFunc(router.Get))))))
+	router.handle("POST /{alias}/rollback", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Rollback)))))))
 
-	router.Router.Handle("DELETE /delete", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Delete)))))))
+	router.handle("GET /trash", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Trash)))))))
 
-	router.Router.Handle("PUT /update", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.UpdateURL)))))))
+	router.handle("POST /{alias}/restore", recovermiddle.New(requestid.New(router.logger.Logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Restore)))))))
 
 	router.StartProcessPopAlias()
 }
@@ -0,0 +1,172 @@
+package urlrouter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/useragent"
+)
+
+type RequestRule struct {
+	OS       string `json:"os" validate:"omitempty,oneof=android ios windows macos linux chromeos"`
+	Device   string `json:"device" validate:"omitempty,oneof=mobile tablet desktop bot"`
+	Language string `json:"language" validate:"omitempty,bcp47_language_tag"`
+	Country  string `json:"country" validate:"omitempty,iso3166_1_alpha2"`
+	URL      string `json:"url" validate:"required,url"`
+}
+
+type RequestRules struct {
+	Rules []RequestRule `json:"rules" validate:"max=20,dive"`
+}
+
+type ResponseRules struct {
+	Alias    string      `json:"alias"`
+	Rules    []urls.Rule `json:"rules"`
+	Fallback string      `json:"fallback,omitempty"`
+	mainresponse.Response
+}
+
+func writeRulesError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	if errors.Is(err, generalerrors.ErrInvalidRules) {
+		logger.Info("rules handler", slog.String("error", err.Error()))
+
+		writeGroupError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidRules)
+		return
+	}
+
+	writeDestinationsError(w, logger, err)
+}
+
+func (router *Router) GetRules(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "get rules handler")
+
+	if !ok {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	url, err := router.urlService.GetRules(r.Context(), alias, sub)
+
+	if err != nil {
+		writeRulesError(w, logger, err)
+		return
+	}
+
+	rules := url.Rules
+
+	if rules == nil {
+		rules = []urls.Rule{}
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseRules{Alias: alias, Rules: rules, Fallback: url.URL, Response: mainresponse.NewOK()})
+}
+
+func (router *Router) SetRules(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "set rules handler")
+
+	if !ok {
+		return
+	}
+
+	req := RequestRules{}
+
+	if !router.decodeGroupRequest(w, r, logger, &req) {
+		return
+	}
+
+	rules := make([]urls.Rule, 0, len(req.Rules))
+
+	for _, rule := range req.Rules {
+		rules = append(rules, urls.Rule{
+			OS:       rule.OS,
+			Device:   rule.Device,
+			Language: rule.Language,
+			Country:  rule.Country,
+			URL:      rule.URL,
+		})
+	}
+
+	alias := r.PathValue("alias")
+	rules, err := router.urlService.SetRules(r.Context(), alias, sub, rules)
+
+	if err != nil {
+		writeRulesError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseRules{Alias: alias, Rules: rules, Response: mainresponse.NewOK()})
+}
+
+func (router *Router) visitor(r *http.Request) urls.Visitor {
+	ua := r.UserAgent()
+
+	return urls.Visitor{
+		OS:        useragent.OS(ua),
+		Device:    useragent.Device(ua),
+		Languages: preferredLanguages(r.Header.Get("Accept-Language")),
+		Country:   router.geo.Country(r.RemoteAddr),
+	}
+}
+
+// preferredLanguages returns the Accept-Language tags ordered by descending q, dropping q=0 and "*".
+func preferredLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	langs := make([]weighted, 0)
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		langs = append(langs, weighted{tag: tag, q: q})
+	}
+
+	slices.SortStableFunc(langs, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+
+		return 0
+	})
+
+	tags := make([]string, 0, len(langs))
+
+	for _, lang := range langs {
+		tags = append(tags, lang.tag)
+	}
+
+	return tags
+}
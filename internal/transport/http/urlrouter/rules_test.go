@@ -0,0 +1,29 @@
+package urlrouter
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPreferredLanguages(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "", want: []string{}},
+		{header: "de", want: []string{"de"}},
+		{header: "fr,de;q=0.9", want: []string{"fr", "de"}},
+		{header: "de;q=0.5, en-US, fr;q=0.8", want: []string{"en-us", "fr", "de"}},
+		{header: "en;q=0.5,de;q=0.5", want: []string{"en", "de"}},
+		{header: "*, de;q=0.1", want: []string{"de"}},
+		{header: "fr;q=0, de", want: []string{"de"}},
+		{header: "fr;q=abc, de", want: []string{"de"}},
+		{header: " , ,DE-at", want: []string{"de-at"}},
+	}
+
+	for _, tt := range tests {
+		if got := preferredLanguages(tt.header); !slices.Equal(got, tt.want) {
+			t.Errorf("preferredLanguages(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
@@ -17,13 +18,67 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-const (
-	aliasRandLength = 6
-)
-
 type RequestSave struct {
-	URL   string `json:"url" validate:"required,url"`
-	Alias string `json:"alias,omitempty"`
+	URL          string     `json:"url" validate:"required,url"`
+	Alias        string     `json:"alias,omitempty" validate:"omitempty,alias"`
+	Title        string     `json:"title,omitempty" validate:"omitempty,max=256"`
+	RedirectCode int        `json:"redirect_code,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" validate:"excluded_with=TTL"`
+	TTL          string     `json:"ttl,omitempty" validate:"excluded_with=ExpiresAt"`
+	MaxClicks    int        `json:"max_clicks,omitempty" validate:"omitempty,min=1"`
+	Password     string     `json:"password,omitempty" validate:"omitempty,min=4,max=72"`
+	ActiveFrom   *time.Time `json:"active_from,omitempty"`
+	ComingSoon   bool       `json:"coming_soon,omitempty"`
+}
+
+func (req RequestSave) expiresAt(now time.Time) (*time.Time, error) {
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+
+		if err != nil || ttl <= 0 {
+			return nil, errors.New("ttl must be a positive duration, for example 72h")
+		}
+
+		expiresAt := now.Add(ttl)
+
+		return &expiresAt, nil
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	return req.ExpiresAt, nil
+}
+
+func (req RequestSave) toURL(ownerUUID string, now time.Time) (urls.URL, error) {
+	expiresAt, err := req.expiresAt(now)
+
+	if err != nil {
+		return urls.URL{}, err
+	}
+
+	if req.ActiveFrom != nil && expiresAt != nil && !req.ActiveFrom.Before(*expiresAt) {
+		return urls.URL{}, generalerrors.ErrInvalidActivation
+	}
+
+	var maxClicks *int
+
+	if req.MaxClicks > 0 {
+		maxClicks = &req.MaxClicks
+	}
+
+	return urls.URL{
+		URL:          req.URL,
+		Alias:        req.Alias,
+		Title:        req.Title,
+		RedirectCode: req.RedirectCode,
+		OwnerUUID:    ownerUUID,
+		ExpiresAt:    expiresAt,
+		MaxClicks:    maxClicks,
+		ActiveFrom:   req.ActiveFrom,
+		ComingSoon:   req.ComingSoon,
+	}, nil
 }
 
 type ResponseSave struct {
@@ -61,6 +116,14 @@ func (router *Router) Save(w http.ResponseWriter, r *http.Request) {
 
 	logger = logger.With("component", "save handler")
 
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
 	req := RequestSave{}
 	err = json.NewDecoder(r.Body).Decode(&req)
 
@@ -125,19 +188,47 @@ func (router *Router) Save(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Alias == "" {
-		out := make([]rune, 0, aliasRandLength)
+	url, err := req.toURL(sub, time.Now())
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		out, err := newSaveResponse(err)
+
+		if err != nil {
+			logger.Error("json marshall", slog.String("error", err.Error()))
 
-		for range aliasRandLength {
-			out = append(out, router.sliceForRandAlias[rand.Int64N(int64(len(router.sliceForRandAlias)))])
+			http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+
+			return
 		}
 
-		req.Alias = string(out)
+		http.Error(w, string(out), http.StatusBadRequest)
+
+		return
 	}
 
-	id, err := router.urlService.SaveAlias(r.Context(), req.URL, req.Alias)
+	url, err = router.urlService.SaveAlias(r.Context(), url, req.Password)
 
 	if err != nil {
+		if destErr := destinationError(err); destErr != nil {
+			logger.Info("save alias handler", slog.String("error", err.Error()))
+
+			out, err := newSaveResponse(destErr)
+
+			if err != nil {
+				logger.Error("json marshall", slog.String("error", err.Error()))
+
+				http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+
+				return
+			}
+
+			http.Error(w, string(out), http.StatusBadRequest)
+
+			return
+		}
+
 		if errors.Is(err, generalerrors.ErrAliasAlreadyExists) {
 			logger.Debug("save alias handler", slog.String("error", err.Error()))
 
@@ -156,7 +247,7 @@ func (router *Router) Save(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			http.Error(w, string(out), http.StatusInternalServerError)
+			http.Error(w, string(out), http.StatusConflict)
 
 			return
 		}
@@ -179,9 +270,9 @@ func (router *Router) Save(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := ResponseSave{
-		ID:       id,
+		ID:       url.ID,
 		Response: mainresponse.NewOK(),
-		Alias:    req.Alias,
+		Alias:    url.Alias,
 	}
 	responseJSON, err := json.Marshal(resp)
 
@@ -0,0 +1,112 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+func writeListError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
+	out, e := newListResponse(err)
+
+	if e != nil {
+		logger.Error("json marshal", slog.String("error", e.Error()))
+
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	http.Error(w, string(out), status)
+}
+
+func (router *Router) Search(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "search handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultListLimit
+
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+
+		if err != nil || limit < 1 || limit > maxListLimit {
+			logger.Info("bad request", slog.String("limit", value))
+
+			writeListError(w, logger, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxListLimit))
+			return
+		}
+	}
+
+	list, nextCursor, err := router.urlService.SearchURLs(r.Context(), sub, query.Get("q"), limit, query.Get("cursor"))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, generalerrors.ErrInvalidSearchQuery):
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			writeListError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidSearchQuery)
+		case errors.Is(err, generalerrors.ErrInvalidCursor):
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			writeListError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidCursor)
+		default:
+			logger.Error("search handler", slog.String("error", err.Error()))
+
+			writeListError(w, logger, http.StatusInternalServerError, errors.New(respforusers.ErrInternalError))
+		}
+
+		return
+	}
+
+	if list == nil {
+		list = []urls.URL{}
+	}
+
+	response := ResponseList{
+		URLs:       list,
+		NextCursor: nextCursor,
+		Response:   mainresponse.NewOK(),
+	}
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success search handler", slog.Int("count", len(list)))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,151 @@
+package urlrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Cwby333/url-shorter/internal/entity/clicks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+)
+
+var errInvalidStatsTime = errors.New("from and to must be RFC3339 timestamps or dates")
+
+type ResponseStats struct {
+	Stats *clicks.Stats `json:"stats,omitempty"`
+	mainresponse.Response
+}
+
+func newStatsResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/urlrouter/stats.go/newStatsResponse"
+
+	response := ResponseStats{
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func writeStatsError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
+	out, e := newStatsResponse(err)
+
+	if e != nil {
+		logger.Error("json marshal", slog.String("error", e.Error()))
+
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	http.Error(w, string(out), status)
+}
+
+func parseStatsTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.DateOnly, value)
+}
+
+func (router *Router) Stats(w http.ResponseWriter, r *http.Request) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return
+	}
+
+	logger = logger.With("component", "stats handler")
+
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
+	alias := r.PathValue("alias")
+	query := r.URL.Query()
+
+	from, errFrom := parseStatsTime(query.Get("from"))
+	to, errTo := parseStatsTime(query.Get("to"))
+
+	if errFrom != nil || errTo != nil {
+		logger.Info("bad request", slog.String("from", query.Get("from")), slog.String("to", query.Get("to")))
+
+		writeStatsError(w, logger, http.StatusBadRequest, errInvalidStatsTime)
+		return
+	}
+
+	stats, err := router.statsService.GetStats(r.Context(), alias, sub, from, to, query.Get("interval"))
+
+	if err != nil {
+		switch {
+		case errors.Is(err, generalerrors.ErrInvalidStatsInterval):
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			writeStatsError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidStatsInterval)
+		case errors.Is(err, generalerrors.ErrInvalidStatsRange):
+			logger.Info("bad request", slog.String("error", err.Error()))
+
+			writeStatsError(w, logger, http.StatusBadRequest, generalerrors.ErrInvalidStatsRange)
+		case errors.Is(err, generalerrors.ErrAliasNotFound):
+			logger.Debug("stats handler", slog.String("error", err.Error()))
+
+			writeStatsError(w, logger, http.StatusNotFound, generalerrors.ErrAliasNotFound)
+		case errors.Is(err, generalerrors.ErrNotAliasOwner):
+			logger.Info("stats handler", slog.String("error", err.Error()))
+
+			writeStatsError(w, logger, http.StatusForbidden, generalerrors.ErrNotAliasOwner)
+		default:
+			logger.Error("stats handler", slog.String("error", err.Error()))
+
+			writeStatsError(w, logger, http.StatusInternalServerError, errors.New(respforusers.ErrInternalError))
+		}
+
+		return
+	}
+
+	response := ResponseStats{
+		Stats:    &stats,
+		Response: mainresponse.NewOK(),
+	}
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success stats handler")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,51 @@
+package urlrouter
+
+import (
+	"net/http"
+
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+)
+
+type ResponseTrash struct {
+	URLs []urls.URL `json:"urls"`
+	mainresponse.Response
+}
+
+func (router *Router) Trash(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "trash handler")
+
+	if !ok {
+		return
+	}
+
+	out, err := router.urlService.ListTrash(r.Context(), sub)
+
+	if err != nil {
+		writeGroupServiceError(w, logger, err)
+		return
+	}
+
+	if out == nil {
+		out = []urls.URL{}
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseTrash{URLs: out, Response: mainresponse.NewOK()})
+}
+
+func (router *Router) Restore(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := groupHandlerContext(w, r, "restore handler")
+
+	if !ok {
+		return
+	}
+
+	url, err := router.urlService.RestoreURL(r.Context(), r.PathValue("alias"), sub)
+
+	if err != nil {
+		writeGroupServiceError(w, logger, err)
+		return
+	}
+
+	writeGroupResponse(w, logger, http.StatusOK, ResponseRollback{URL: &url, Response: mainresponse.NewOK()})
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/Cwby333/url-shorter/internal/entity/urls"
 	"github.com/Cwby333/url-shorter/internal/generalerrors"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
 	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
@@ -15,14 +17,21 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+type RequestActivation struct {
+	ActiveFrom *time.Time `json:"active_from"`
+	ComingSoon bool       `json:"coming_soon"`
+}
+
 type RequestUpdateURL struct {
-	Alias  string `json:"alias" validate:"required"`
-	NewURL string `json:"url" validate:"required"`
+	Alias      string             `json:"alias" validate:"required"`
+	NewURL     string             `json:"url" validate:"required_without=Activation,omitempty,url"`
+	Activation *RequestActivation `json:"activation,omitempty"`
 }
 
 type ResponseUpdateURL struct {
 	mainresponse.Response
-	URL string `json:"url"`
+	URL        string             `json:"url"`
+	Activation *RequestActivation `json:"activation,omitempty"`
 }
 
 func newUpdateURLResponse(err error) ([]byte, error) {
@@ -41,6 +50,64 @@ func newUpdateURLResponse(err error) ([]byte, error) {
 	return out, nil
 }
 
+func writeUpdateError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	badRequest := destinationError(err)
+
+	if badRequest == nil && errors.Is(err, generalerrors.ErrInvalidActivation) {
+		badRequest = generalerrors.ErrInvalidActivation
+	}
+
+	if badRequest != nil {
+		logger.Info("update url handler", slog.String("error", err.Error()))
+
+		out, err := newUpdateURLResponse(badRequest)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, respforusers.ErrBadRequest, http.StatusBadRequest)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, generalerrors.ErrAliasNotFound) {
+		logger.Debug("update url handler", slog.String("error", err.Error()))
+
+		out, err := newUpdateURLResponse(generalerrors.ErrAliasNotFound)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, "alias not found", http.StatusNotFound)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, generalerrors.ErrNotAliasOwner) {
+		logger.Info("update url handler", slog.String("error", err.Error()))
+
+		out, err := newUpdateURLResponse(generalerrors.ErrNotAliasOwner)
+
+		if err != nil {
+			logger.Error("json marshal", slog.String("error", err.Error()))
+
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		http.Error(w, string(out), http.StatusForbidden)
+		return
+	}
+
+	logger.Error("update url handler", slog.String("error", err.Error()))
+
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
 func (router *Router) UpdateURL(w http.ResponseWriter, r *http.Request) {
 	logger, ok := r.Context().Value("logger").(*slog.Logger)
 
@@ -52,6 +119,14 @@ func (router *Router) UpdateURL(w http.ResponseWriter, r *http.Request) {
 
 	logger = logger.With("component", "update url handler")
 
+	sub, ok := subject(r)
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return
+	}
+
 	req := RequestUpdateURL{}
 	err = json.NewDecoder(r.Body).Decode(&req)
 
@@ -108,33 +183,26 @@ func (router *Router) UpdateURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = router.urlService.UpdateURL(r.Context(), req.NewURL, req.Alias)
-
-	if err != nil {
-		if errors.Is(err, generalerrors.ErrAliasNotFound) {
-			logger.Debug("update url handler", slog.String("error", err.Error()))
-
-			out, err := newUpdateURLResponse(err)
-
-			if err != nil {
-				logger.Error("json marshal", slog.String("error", err.Error()))
-
-				http.Error(w, "alias not found", http.StatusNotFound)
-			}
+	var activation *urls.Activation
 
-			http.Error(w, string(out), http.StatusNotFound)
-			return
+	if req.Activation != nil {
+		activation = &urls.Activation{
+			ActiveFrom: req.Activation.ActiveFrom,
+			ComingSoon: req.Activation.ComingSoon,
 		}
+	}
 
-		logger.Error("update url handler", slog.String("error", err.Error()))
+	updated, err := router.urlService.UpdateURL(r.Context(), req.NewURL, req.Alias, sub, activation)
 
-		http.Error(w, "internal error", http.StatusInternalServerError)
+	if err != nil {
+		writeUpdateError(w, logger, err)
 		return
 	}
 
 	response := ResponseUpdateURL{
-		Response: mainresponse.NewOK(),
-		URL:      req.NewURL,
+		Response:   mainresponse.NewOK(),
+		URL:        updated.URL,
+		Activation: req.Activation,
 	}
 	data, err := json.Marshal(response)
 
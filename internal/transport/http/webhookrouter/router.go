@@ -0,0 +1,60 @@
+package webhookrouter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/jwtmiddle"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/limitermidde"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/logging"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/recovermiddle"
+	"github.com/Cwby333/url-shorter/internal/transport/http/middlewares/requestid"
+	"github.com/Cwby333/url-shorter/internal/transport/http/ratelimiter"
+	"github.com/go-playground/validator/v10"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, ownerUUID, rawURL string, events []string) (webhooks.Webhook, error)
+	ListWebhooks(ctx context.Context, ownerUUID string) ([]webhooks.Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerUUID string, id int64) error
+	ListDeliveries(ctx context.Context, ownerUUID string, id int64, status string) ([]webhooks.Delivery, error)
+}
+
+type Router struct {
+	Router    *http.ServeMux
+	service   WebhookService
+	limiter   ratelimiter.Limiter
+	logger    *slog.Logger
+	validator *validator.Validate
+}
+
+func New(service WebhookService, logger *slog.Logger, limiter ratelimiter.Limiter) (Router, error) {
+	const op = "internal/transport/http/webhookrouter/New"
+
+	if service == (WebhookService)(nil) {
+		logger.Error("nil pointer in WebhookService interface", slog.String("op", op))
+
+		return Router{}, generalerrors.ErrNilPointerInInterface
+	}
+
+	return Router{
+		Router:    http.NewServeMux(),
+		service:   service,
+		limiter:   limiter,
+		logger:    logger,
+		validator: validator.New(validator.WithRequiredStructEnabled()),
+	}, nil
+}
+
+func (router Router) Run() {
+	router.Router.Handle("POST /{$}", recovermiddle.New(requestid.New(router.logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Create)))))))
+
+	router.Router.Handle("GET /{$}", recovermiddle.New(requestid.New(router.logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.List)))))))
+
+	router.Router.Handle("DELETE /{id}", recovermiddle.New(requestid.New(router.logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Delete)))))))
+
+	router.Router.Handle("GET /{id}/deliveries", recovermiddle.New(requestid.New(router.logger)(logging.New(jwtmiddle.NewAccess(limitermidde.New(router.limiter)(http.HandlerFunc(router.Deliveries)))))))
+}
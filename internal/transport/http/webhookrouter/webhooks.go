@@ -0,0 +1,288 @@
+package webhookrouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Cwby333/url-shorter/internal/entity/webhooks"
+	"github.com/Cwby333/url-shorter/internal/generalerrors"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/mainresponse"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/respforusers"
+	"github.com/Cwby333/url-shorter/internal/transport/http/lib/typeasserterror"
+	validaterequests "github.com/Cwby333/url-shorter/internal/transport/http/lib/validaterequsts"
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	errInvalidWebhookID = errors.New("id must be a positive number")
+	errInvalidStatus    = errors.New("status must be one of: pending delivered dead")
+)
+
+var badRequestErrors = []error{
+	generalerrors.ErrInvalidWebhookEvents,
+	generalerrors.ErrDestinationScheme,
+	generalerrors.ErrDestinationHost,
+	generalerrors.ErrDestinationPrivate,
+	generalerrors.ErrDestinationLoop,
+	generalerrors.ErrDestinationBlocked,
+}
+
+type RequestWebhook struct {
+	URL    string   `json:"url" validate:"required,url"`
+	Events []string `json:"events" validate:"required,min=1,max=4,dive,oneof=url.created url.updated url.deleted url.clicked"`
+}
+
+type ResponseWebhook struct {
+	Webhook *webhooks.Webhook `json:"webhook,omitempty"`
+	mainresponse.Response
+}
+
+type ResponseWebhooks struct {
+	Webhooks []webhooks.Webhook `json:"webhooks"`
+	mainresponse.Response
+}
+
+type ResponseDeliveries struct {
+	Deliveries []webhooks.Delivery `json:"deliveries"`
+	mainresponse.Response
+}
+
+func newWebhookResponse(err error) ([]byte, error) {
+	const op = "internal/transport/http/webhookrouter/newWebhookResponse"
+
+	response := ResponseWebhook{
+		Response: mainresponse.NewError(err.Error()),
+	}
+
+	out, err := json.Marshal(response)
+
+	if err != nil {
+		return []byte{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return out, nil
+}
+
+func writeError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
+	out, e := newWebhookResponse(err)
+
+	if e != nil {
+		logger.Error("json marshal", slog.String("error", e.Error()))
+
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	http.Error(w, string(out), status)
+}
+
+func writeServiceError(w http.ResponseWriter, logger *slog.Logger, err error) {
+	for _, target := range badRequestErrors {
+		if errors.Is(err, target) {
+			logger.Info("webhook handler", slog.String("error", err.Error()))
+
+			writeError(w, logger, http.StatusBadRequest, target)
+			return
+		}
+	}
+
+	if errors.Is(err, generalerrors.ErrWebhookNotFound) {
+		logger.Debug("webhook handler", slog.String("error", err.Error()))
+
+		writeError(w, logger, http.StatusNotFound, generalerrors.ErrWebhookNotFound)
+		return
+	}
+
+	logger.Error("webhook handler", slog.String("error", err.Error()))
+
+	writeError(w, logger, http.StatusInternalServerError, errors.New(respforusers.ErrInternalError))
+}
+
+func writeResponse(w http.ResponseWriter, logger *slog.Logger, status int, response any) {
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		logger.Error("json marshal", slog.String("error", err.Error()))
+
+		http.Error(w, respforusers.ErrInternalError, http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("success webhook handler")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(data)
+
+	if err != nil {
+		logger.Error("response write", slog.String("error", err.Error()))
+	}
+}
+
+func handlerContext(w http.ResponseWriter, r *http.Request, component string) (*slog.Logger, string, bool) {
+	logger, ok := r.Context().Value("logger").(*slog.Logger)
+
+	err := typeasserterror.Check(ok, w, slog.Default())
+
+	if err != nil {
+		return nil, "", false
+	}
+
+	logger = logger.With("component", component)
+
+	claims, ok := r.Context().Value("claims").(jwt.MapClaims)
+
+	sub := ""
+
+	if ok {
+		sub, ok = claims["sub"].(string)
+	}
+
+	err = typeasserterror.Check(ok, w, logger)
+
+	if err != nil {
+		return nil, "", false
+	}
+
+	return logger, sub, true
+}
+
+func webhookID(w http.ResponseWriter, r *http.Request, logger *slog.Logger) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+
+	if err != nil || id < 1 {
+		logger.Info("bad request", slog.String("id", r.PathValue("id")))
+
+		writeError(w, logger, http.StatusBadRequest, errInvalidWebhookID)
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (router Router) Create(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := handlerContext(w, r, "create webhook handler")
+
+	if !ok {
+		return
+	}
+
+	req := RequestWebhook{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		writeError(w, logger, http.StatusBadRequest, errors.New(respforusers.ErrBadRequest))
+		return
+	}
+
+	r.Body.Close()
+
+	err = router.validator.Struct(req)
+
+	if err != nil {
+		logger.Info("bad request", slog.String("error", err.Error()))
+
+		writeError(w, logger, http.StatusBadRequest, errors.New(strings.Join(validaterequests.Validate(err.(validator.ValidationErrors)), ", ")))
+		return
+	}
+
+	hook, err := router.service.CreateWebhook(r.Context(), sub, req.URL, req.Events)
+
+	if err != nil {
+		writeServiceError(w, logger, err)
+		return
+	}
+
+	writeResponse(w, logger, http.StatusCreated, ResponseWebhook{Webhook: &hook, Response: mainresponse.NewOK()})
+}
+
+func (router Router) List(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := handlerContext(w, r, "list webhooks handler")
+
+	if !ok {
+		return
+	}
+
+	out, err := router.service.ListWebhooks(r.Context(), sub)
+
+	if err != nil {
+		writeServiceError(w, logger, err)
+		return
+	}
+
+	if out == nil {
+		out = []webhooks.Webhook{}
+	}
+
+	writeResponse(w, logger, http.StatusOK, ResponseWebhooks{Webhooks: out, Response: mainresponse.NewOK()})
+}
+
+func (router Router) Delete(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := handlerContext(w, r, "delete webhook handler")
+
+	if !ok {
+		return
+	}
+
+	id, ok := webhookID(w, r, logger)
+
+	if !ok {
+		return
+	}
+
+	err := router.service.DeleteWebhook(r.Context(), sub, id)
+
+	if err != nil {
+		writeServiceError(w, logger, err)
+		return
+	}
+
+	writeResponse(w, logger, http.StatusOK, ResponseWebhook{Response: mainresponse.NewOK()})
+}
+
+func (router Router) Deliveries(w http.ResponseWriter, r *http.Request) {
+	logger, sub, ok := handlerContext(w, r, "webhook deliveries handler")
+
+	if !ok {
+		return
+	}
+
+	id, ok := webhookID(w, r, logger)
+
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+
+	switch status {
+	case "", webhooks.StatusPending, webhooks.StatusDelivered, webhooks.StatusDead:
+	default:
+		logger.Info("bad request", slog.String("status", status))
+
+		writeError(w, logger, http.StatusBadRequest, errInvalidStatus)
+		return
+	}
+
+	out, err := router.service.ListDeliveries(r.Context(), sub, id, status)
+
+	if err != nil {
+		writeServiceError(w, logger, err)
+		return
+	}
+
+	if out == nil {
+		out = []webhooks.Delivery{}
+	}
+
+	writeResponse(w, logger, http.StatusOK, ResponseDeliveries{Deliveries: out, Response: mainresponse.NewOK()})
+}
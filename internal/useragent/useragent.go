@@ -0,0 +1,118 @@
+package useragent
+
+import "strings"
+
+const (
+	Unknown = "Other"
+
+	OSAndroid  = "android"
+	OSIOS      = "ios"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+var browsers = []struct {
+	token  string
+	family string
+}{
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"yabrowser/", "Yandex"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"chromium/", "Chrome"},
+	{"safari/", "Safari"},
+	{"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"},
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+}
+
+var systems = []struct {
+	token string
+	os    string
+}{
+	{"android", OSAndroid},
+	{"iphone", OSIOS},
+	{"ipad", OSIOS},
+	{"ipod", OSIOS},
+	{"cros", OSChromeOS},
+	{"windows", OSWindows},
+	{"macintosh", OSMacOS},
+	{"mac os x", OSMacOS},
+	{"linux", OSLinux},
+}
+
+var bots = []string{"bot", "crawler", "spider", "slurp", "facebookexternalhit", "preview"}
+
+func Browser(ua string) string {
+	ua = strings.ToLower(ua)
+
+	if ua == "" {
+		return Unknown
+	}
+
+	for _, bot := range bots {
+		if strings.Contains(ua, bot) {
+			return "Bot"
+		}
+	}
+
+	for _, browser := range browsers {
+		if strings.Contains(ua, browser.token) {
+			return browser.family
+		}
+	}
+
+	return Unknown
+}
+
+func OS(ua string) string {
+	ua = strings.ToLower(ua)
+
+	for _, system := range systems {
+		if strings.Contains(ua, system.token) {
+			return system.os
+		}
+	}
+
+	return Unknown
+}
+
+func Device(ua string) string {
+	ua = strings.ToLower(ua)
+
+	if ua == "" {
+		return Unknown
+	}
+
+	for _, bot := range bots {
+		if strings.Contains(ua, bot) {
+			return DeviceBot
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad"), strings.Contains(ua, "tablet"):
+		return DeviceTablet
+	case strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "mobile"), strings.Contains(ua, "iphone"), strings.Contains(ua, "ipod"):
+		return DeviceMobile
+	}
+
+	return DeviceDesktop
+}
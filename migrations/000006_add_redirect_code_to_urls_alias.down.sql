@@ -0,0 +1 @@
+ALTER TABLE urls_alias DROP COLUMN redirect_code;
\ No newline at end of file
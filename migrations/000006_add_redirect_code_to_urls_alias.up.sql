@@ -0,0 +1 @@
+ALTER TABLE urls_alias ADD COLUMN redirect_code INT NOT NULL DEFAULT 0;
\ No newline at end of file
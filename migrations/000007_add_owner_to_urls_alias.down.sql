@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS urls_alias_owner_uuid_idx;
+
+ALTER TABLE urls_alias DROP COLUMN owner_uuid;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE urls_alias ADD COLUMN owner_uuid UUID REFERENCES users(uuid) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS urls_alias_owner_uuid_idx ON urls_alias(owner_uuid);
\ No newline at end of file
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS urls_alias_owner_clicks_idx;
+
+DROP INDEX IF EXISTS urls_alias_owner_id_idx;
+
+ALTER TABLE urls_alias DROP COLUMN clicks;
+
+ALTER TABLE urls_alias DROP COLUMN updated_at;
+
+ALTER TABLE urls_alias DROP COLUMN created_at;
\ No newline at end of file
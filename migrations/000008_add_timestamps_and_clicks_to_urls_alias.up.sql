@@ -0,0 +1,9 @@
+ALTER TABLE urls_alias ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE urls_alias ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE urls_alias ADD COLUMN clicks BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS urls_alias_owner_id_idx ON urls_alias(owner_uuid, id);
+
+CREATE INDEX IF NOT EXISTS urls_alias_owner_clicks_idx ON urls_alias(owner_uuid, clicks, id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS urls_alias_archive;
+
+DROP INDEX IF EXISTS urls_alias_expires_at_idx;
+
+ALTER TABLE urls_alias DROP COLUMN expires_at;
\ No newline at end of file
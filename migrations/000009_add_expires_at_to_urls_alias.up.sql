@@ -0,0 +1,5 @@
+ALTER TABLE urls_alias ADD COLUMN expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS urls_alias_expires_at_idx ON urls_alias(expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS urls_alias_archive(id BIGINT PRIMARY KEY, url TEXT NOT NULL, alias TEXT NOT NULL, owner_uuid UUID, created_at TIMESTAMPTZ NOT NULL, expires_at TIMESTAMPTZ, archived_at TIMESTAMPTZ NOT NULL DEFAULT now());
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE urls_alias DROP COLUMN remaining_clicks;
+
+ALTER TABLE urls_alias DROP COLUMN max_clicks;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE urls_alias ADD COLUMN max_clicks INT CHECK (max_clicks > 0);
+
+ALTER TABLE urls_alias ADD COLUMN remaining_clicks INT CHECK (remaining_clicks >= 0);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE urls_alias DROP COLUMN password_hash;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE urls_alias ADD COLUMN password_hash TEXT;
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE TABLE IF NOT EXISTS clicks(id BIGSERIAL PRIMARY KEY, url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, alias TEXT NOT NULL, clicked_at TIMESTAMPTZ NOT NULL, referrer TEXT NOT NULL DEFAULT '', user_agent TEXT NOT NULL DEFAULT '', ip TEXT NOT NULL DEFAULT '', request_id TEXT NOT NULL DEFAULT '');
+
+CREATE INDEX IF NOT EXISTS clicks_url_id_clicked_at_idx ON clicks(url_id, clicked_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS clicks_daily_dimensions;
+
+DROP TABLE IF EXISTS clicks_hourly;
+
+ALTER TABLE clicks DROP COLUMN IF EXISTS browser, DROP COLUMN IF EXISTS country;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS country TEXT NOT NULL DEFAULT '', ADD COLUMN IF NOT EXISTS browser TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS clicks_hourly(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, bucket TIMESTAMPTZ NOT NULL, count BIGINT NOT NULL DEFAULT 0, PRIMARY KEY(url_id, bucket));
+
+CREATE TABLE IF NOT EXISTS clicks_daily_dimensions(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, day DATE NOT NULL, dimension TEXT NOT NULL, value TEXT NOT NULL, count BIGINT NOT NULL DEFAULT 0, PRIMARY KEY(url_id, day, dimension, value));
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS popular_aliases;
+
+CREATE TABLE IF NOT EXISTS most_popular_aliasses(id BIGSERIAL PRIMARY KEY, alias TEXT NOT NULL, count_of_req INT NOT NULL);
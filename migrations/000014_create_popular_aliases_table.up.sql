@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS popular_aliases(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, bucket TIMESTAMPTZ NOT NULL, count BIGINT NOT NULL DEFAULT 0, PRIMARY KEY(url_id, bucket));
+
+CREATE INDEX IF NOT EXISTS popular_aliases_bucket_idx ON popular_aliases(bucket);
+
+DROP TABLE IF EXISTS most_popular_aliasses;
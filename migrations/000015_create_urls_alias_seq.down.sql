@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS urls_alias_seq;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE SEQUENCE IF NOT EXISTS urls_alias_seq;
\ No newline at end of file
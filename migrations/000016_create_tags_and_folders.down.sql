@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS url_tags;
+
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS tags;
+
+DROP TABLE IF EXISTS folders;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS folders(id BIGSERIAL PRIMARY KEY, owner_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE, name TEXT NOT NULL, created_at TIMESTAMPTZ NOT NULL DEFAULT now(), UNIQUE(owner_uuid, name));
+
+CREATE TABLE IF NOT EXISTS tags(id BIGSERIAL PRIMARY KEY, owner_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE, name TEXT NOT NULL, created_at TIMESTAMPTZ NOT NULL DEFAULT now(), UNIQUE(owner_uuid, name));
+
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS folder_id BIGINT REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS urls_alias_folder_id_idx ON urls_alias(folder_id);
+
+CREATE TABLE IF NOT EXISTS url_tags(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE, PRIMARY KEY(url_id, tag_id));
+
+CREATE INDEX IF NOT EXISTS url_tags_tag_id_idx ON url_tags(tag_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS tags_name_search_idx;
+
+DROP INDEX IF EXISTS urls_alias_search_vector_idx;
+
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS title;
@@ -0,0 +1,7 @@
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (setweight(to_tsvector('simple', title), 'A') || setweight(to_tsvector('simple', alias || ' ' || regexp_replace(alias, '[^[:alnum:]]+', ' ', 'g')), 'A') || setweight(to_tsvector('simple', regexp_replace(url, '[^[:alnum:]]+', ' ', 'g')), 'B')) STORED;
+
+CREATE INDEX IF NOT EXISTS urls_alias_search_vector_idx ON urls_alias USING GIN(search_vector);
+
+CREATE INDEX IF NOT EXISTS tags_name_search_idx ON tags USING GIN(to_tsvector('simple', name));
@@ -0,0 +1,5 @@
+ALTER TABLE clicks DROP COLUMN IF EXISTS destination, DROP COLUMN IF EXISTS variant;
+
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS sticky_destinations;
+
+DROP TABLE IF EXISTS url_destinations;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS url_destinations(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, position INT NOT NULL, url TEXT NOT NULL, weight INT NOT NULL CHECK (weight > 0), PRIMARY KEY(url_id, position));
+
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS sticky_destinations BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE clicks ADD COLUMN IF NOT EXISTS variant INT NOT NULL DEFAULT 0, ADD COLUMN IF NOT EXISTS destination TEXT NOT NULL DEFAULT '';
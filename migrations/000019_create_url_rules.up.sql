@@ -0,0 +1 @@
+CREATE TABLE IF NOT EXISTS url_rules(url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, position INT NOT NULL, os TEXT NOT NULL DEFAULT '', device TEXT NOT NULL DEFAULT '', language TEXT NOT NULL DEFAULT '', country TEXT NOT NULL DEFAULT '', url TEXT NOT NULL, PRIMARY KEY(url_id, position));
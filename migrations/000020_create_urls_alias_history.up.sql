@@ -0,0 +1 @@
+CREATE TABLE IF NOT EXISTS urls_alias_history(id BIGSERIAL PRIMARY KEY, url_id BIGINT NOT NULL REFERENCES urls_alias(id) ON DELETE CASCADE, version INT NOT NULL, action TEXT NOT NULL, changed_by UUID, old_url TEXT NOT NULL, new_url TEXT NOT NULL DEFAULT '', changed_at TIMESTAMPTZ NOT NULL DEFAULT now(), UNIQUE(url_id, version));
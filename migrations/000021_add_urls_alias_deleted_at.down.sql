@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS urls_alias_deleted_at_idx;
+
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS urls_alias_deleted_at_idx ON urls_alias(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE urls_alias DROP COLUMN IF EXISTS coming_soon, DROP COLUMN IF EXISTS active_from;
@@ -0,0 +1 @@
+ALTER TABLE urls_alias ADD COLUMN IF NOT EXISTS active_from TIMESTAMPTZ, ADD COLUMN IF NOT EXISTS coming_soon BOOLEAN NOT NULL DEFAULT false;
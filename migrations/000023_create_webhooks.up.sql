@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS webhooks(id BIGSERIAL PRIMARY KEY, owner_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE, url TEXT NOT NULL, events TEXT[] NOT NULL, secret TEXT NOT NULL, active BOOLEAN NOT NULL DEFAULT true, created_at TIMESTAMPTZ NOT NULL DEFAULT now());
+
+CREATE INDEX IF NOT EXISTS webhooks_owner_uuid_idx ON webhooks(owner_uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(id BIGSERIAL PRIMARY KEY, webhook_id BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE, event_id UUID, event TEXT NOT NULL, payload JSONB NOT NULL, status TEXT NOT NULL DEFAULT 'pending', attempts INT NOT NULL DEFAULT 0, next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(), last_error TEXT NOT NULL DEFAULT '', response_code INT NOT NULL DEFAULT 0, created_at TIMESTAMPTZ NOT NULL DEFAULT now(), delivered_at TIMESTAMPTZ);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries(webhook_id, id DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_event_id_idx ON webhook_deliveries(webhook_id, event_id);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS outbox(id BIGSERIAL PRIMARY KEY, event_id UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE, aggregate TEXT NOT NULL, aggregate_id TEXT NOT NULL, event TEXT NOT NULL, payload JSONB NOT NULL, attempts INT NOT NULL DEFAULT 0, next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(), last_error TEXT NOT NULL DEFAULT '', created_at TIMESTAMPTZ NOT NULL DEFAULT now(), published_at TIMESTAMPTZ, dead_at TIMESTAMPTZ);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(next_attempt_at, id) WHERE published_at IS NULL AND dead_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_published_at_idx ON outbox(published_at) WHERE published_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_dead_at_idx ON outbox(dead_at) WHERE dead_at IS NOT NULL;